@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,11 +15,45 @@ import (
 	"github.com/user/cpu-monitor/internal/ui"
 )
 
+// pidList collects repeated -pid flags; each may also be comma-separated.
+type pidList []int32
+
+func (p *pidList) String() string {
+	parts := make([]string, len(*p))
+	for i, pid := range *p {
+		parts[i] = strconv.Itoa(int(pid))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (p *pidList) Set(value string) error {
+	for _, field := range strings.Split(value, ",") {
+		pid, err := strconv.ParseInt(strings.TrimSpace(field), 10, 32)
+		if err != nil || pid <= 0 {
+			return fmt.Errorf("invalid pid %q", field)
+		}
+		*p = append(*p, int32(pid))
+	}
+	return nil
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "run" {
+		os.Exit(runCommand(os.Args[2:]))
+	}
+
+	var pids pidList
+	flag.Var(&pids, "pid", "Monitor only this PID and its descendants (repeatable)")
+
 	var (
 		refreshRate = flag.Int("refresh", 500, "Refresh rate in milliseconds (default: 500)")
 		historySize = flag.Int("history", 120, "Number of history points to keep (default: 120)")
 		avgSize     = flag.Int("avg", 10, "Moving average window size (default: 10)")
+		sysfsRoot   = flag.String("sysfs", config.DefaultConfig.SysfsRoot, "Path where sysfs is mounted (default: /sys)")
+		procfsRoot  = flag.String("procfs", config.DefaultConfig.ProcfsRoot, "Path where procfs is mounted (default: /proc)")
+		cgroupRoot  = flag.String("cgroupfs", config.DefaultConfig.CgroupRoot, "Path where the cgroup v2 hierarchy is mounted (default: /sys/fs/cgroup)")
+		tempSensor  = flag.String("sensor", "", "Temperature sensor key to use as CPU temperature (default: auto)")
+		namePattern = flag.String("name", "", "Monitor only processes whose name or command line matches this regexp")
 		help        = flag.Bool("help", false, "Show help message")
 	)
 
@@ -37,10 +74,26 @@ func main() {
 		*refreshRate = 5000
 	}
 
+	var scopeName *regexp.Regexp
+	if *namePattern != "" {
+		re, err := regexp.Compile(*namePattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -name pattern: %v\n", err)
+			os.Exit(2)
+		}
+		scopeName = re
+	}
+
 	cfg := config.Config{
 		RefreshRate:   time.Duration(*refreshRate) * time.Millisecond,
 		HistorySize:   *historySize,
 		MovingAvgSize: *avgSize,
+		SysfsRoot:     *sysfsRoot,
+		ProcfsRoot:    *procfsRoot,
+		CgroupRoot:    *cgroupRoot,
+		TempSensor:    *tempSensor,
+		ScopePIDs:     pids,
+		ScopeName:     scopeName,
 	}
 
 	model := ui.NewModel(cfg)
@@ -66,25 +119,48 @@ A cyberpunk-themed terminal CPU monitoring tool with real-time visualization.
 
 USAGE:
     cpu-monitor [OPTIONS]
+    cpu-monitor run [-refresh ms] [-no-tui] [-top n] -- <command> [args...]
 
 OPTIONS:
     -refresh <ms>    Set refresh rate in milliseconds (100-5000, default: 500)
     -history <n>     Number of history points to keep (default: 120)
     -avg <n>         Moving average window size (default: 10)
+    -sysfs <path>    Path where sysfs is mounted (default: /sys)
+    -procfs <path>   Path where procfs is mounted (default: /proc)
+    -cgroupfs <path> Path where cgroup v2 is mounted (default: /sys/fs/cgroup)
+    -sensor <key>    Temperature sensor used as CPU temperature (default: auto)
+    -pid <pid>       Monitor only this process and its descendants (repeatable)
+    -name <regexp>   Monitor only processes whose name or command line matches
     -help            Show this help message
 
 KEYBOARD CONTROLS:
     q, Ctrl+C        Quit the application
+    h                Toggle the help screen
     r                Reset history
     p                Pause/unpause monitoring
+    Tab, Shift+Tab   Switch between screens (dashboard, sensors, processes,
+                     tree, pressure, cgroups, disks, network, IRQs,
+                     frequency, C-states)
+    g                Group CPU bars by socket, NUMA node and core (dashboard)
+    Up/Down, PgUp/PgDn
+                     Select a process (processes and tree screens)
+    o, O             Change sort column / reverse sort order (processes)
+    Left/Right, Space
+                     Collapse/expand the selected branch (tree)
+    k                Send a signal to the selected process (Unix only)
+    n                Change the nice value of the selected process (Unix only)
+    Enter, Esc       Open/close the detail screen of the selected process
+    l, v, b          Show/hide loopback, veth and bridge interfaces (network)
 
 FEATURES:
-    • Real-time CPU usage monitoring with per-core breakdown
-    • Visual ASCII graphs showing CPU history
-    • Memory usage tracking
-    • System load average display
-    • Process count monitoring
-    • CPU temperature display (when available)
+    • Real-time CPU usage with per-core, per-state breakdown and topology
+    • Visual ASCII graphs showing CPU history, with thermal throttle markers
+    • Memory, swap and paging tracking
+    • Load average, scheduler rates and pressure stall information
+    • Process table and tree with signals, renice and per-process detail
+    • Cgroup v2 quota, throttling and per-group usage
+    • Disk, network and per-CPU interrupt activity
+    • Sensors, thermal zones, cpufreq policies and C-state residency
     • Animated cyberpunk aesthetic with neon colors
 
 VISUAL INDICATORS:
@@ -96,6 +172,9 @@ EXAMPLES:
     cpu-monitor                      # Run with default settings
     cpu-monitor -refresh 1000        # Update every second
     cpu-monitor -history 60 -avg 5   # Keep 60 history points, 5-point average
+    cpu-monitor -pid 1234 -pid 5678  # Watch two processes and their children
+    cpu-monitor -name '^nginx'       # Watch every nginx process
+    cpu-monitor run -- make -j8      # Watch a build, then print a summary
 
 Created with ♥ for the terminal
 `
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/user/cpu-monitor/internal/config"
+	"github.com/user/cpu-monitor/internal/metrics"
+	"github.com/user/cpu-monitor/internal/ui"
+)
+
+// runCommand implements "cpu-monitor run -- <cmd args>": it starts the
+// command, shows the live view scoped to its process tree and prints a
+// resource summary when it exits. The command's exit code is returned.
+func runCommand(args []string) int {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	refreshRate := fs.Int("refresh", 500, "Refresh rate in milliseconds (default: 500)")
+	historySize := fs.Int("history", 120, "Number of history points to keep (default: 120)")
+	noTUI := fs.Bool("no-tui", false, "Don't show the live view, only print the summary")
+	top := fs.Int("top", 5, "Number of busiest processes in the summary (default: 5)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: cpu-monitor run [OPTIONS] -- <command> [args...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cmdArgs := fs.Args()
+	if len(cmdArgs) == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	if *refreshRate < 100 {
+		*refreshRate = 100
+	}
+	if *refreshRate > 5000 {
+		*refreshRate = 5000
+	}
+
+	// The live view needs the terminal; in CI the command keeps its stdio
+	interactive := !*noTUI && isTerminal(os.Stdin) && isTerminal(os.Stdout)
+
+	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
+	var stdoutFile, stderrFile *os.File
+	if interactive {
+		var err error
+		if stdoutFile, err = os.CreateTemp("", "cpu-monitor-stdout-*"); err != nil {
+			fmt.Fprintf(os.Stderr, "cpu-monitor: %v\n", err)
+			return 1
+		}
+		defer os.Remove(stdoutFile.Name())
+		if stderrFile, err = os.CreateTemp("", "cpu-monitor-stderr-*"); err != nil {
+			fmt.Fprintf(os.Stderr, "cpu-monitor: %v\n", err)
+			return 1
+		}
+		defer os.Remove(stderrFile.Name())
+		cmd.Stdout = stdoutFile
+		cmd.Stderr = stderrFile
+	} else {
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+
+	// Without the live view, and once it is closed, Ctrl+C reaches the
+	// command through the terminal; keep running so the summary still gets
+	// printed. Handled signals are reset for the child. The live view puts
+	// the terminal in raw mode, so there Ctrl+C is forwarded below.
+	signal.Notify(make(chan os.Signal, 1), os.Interrupt, syscall.SIGQUIT)
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "cpu-monitor: %v\n", err)
+		return 127
+	}
+
+	cfg := config.DefaultConfig
+	cfg.RefreshRate = time.Duration(*refreshRate) * time.Millisecond
+	cfg.HistorySize = *historySize
+	cfg.ScopePIDs = []int32{int32(cmd.Process.Pid)}
+	cfg.ScopeLabel = "run: " + strings.Join(cmdArgs, " ")
+
+	collector := metrics.NewCollector(ui.CollectorOptions(cfg))
+	recorder := metrics.NewRunRecorder(cfg.HistorySize)
+
+	done := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+
+	if interactive {
+		model := ui.NewModelWithCollector(cfg, collector).WithMetricsHook(recorder.Record)
+		p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
+		go func() {
+			<-done
+			p.Quit()
+		}()
+		final, err := p.Run()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cpu-monitor: %v\n", err)
+		}
+
+		select {
+		case <-done:
+		default:
+			if m, ok := final.(ui.Model); ok && m.Interrupted() {
+				fmt.Fprintf(os.Stderr, "cpu-monitor: interrupting pid %d...\n", cmd.Process.Pid)
+				interrupt(cmd.Process)
+			} else {
+				fmt.Fprintf(os.Stderr, "cpu-monitor: waiting for pid %d to exit...\n", cmd.Process.Pid)
+			}
+		}
+	}
+
+	// Headless mode samples here for the whole run; after the live view
+	// is closed early it covers the rest of the command's life
+	sampleUntil(collector, recorder, cfg.RefreshRate, done)
+	wall := time.Since(start)
+
+	if interactive {
+		replay(stdoutFile, os.Stdout)
+		replay(stderrFile, os.Stderr)
+	}
+
+	exitCode := exitStatus(cmd.ProcessState)
+	printRunSummary(os.Stderr, cmdArgs, cmd.ProcessState, exitCode, wall, recorder.Stats(*top))
+	return exitCode
+}
+
+// sampleUntil feeds the recorder until done is closed.
+func sampleUntil(collector *metrics.Collector, recorder *metrics.RunRecorder, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if m, err := collector.Collect(); err == nil {
+				recorder.Record(m)
+			}
+		}
+	}
+}
+
+// interrupt passes Ctrl+C on to the command, killing it where interrupts
+// can't be sent (Windows).
+func interrupt(p *os.Process) {
+	if err := p.Signal(os.Interrupt); err != nil {
+		p.Kill()
+	}
+}
+
+// replay copies captured command output to the real stream once the
+// alternate screen is gone.
+func replay(f *os.File, w io.Writer) {
+	if _, err := f.Seek(0, io.SeekStart); err == nil {
+		io.Copy(w, f)
+	}
+	f.Close()
+}
+
+// exitStatus maps the command's end state to a shell-style exit code,
+// using 128+N for commands killed by signal N.
+func exitStatus(state *os.ProcessState) int {
+	if state == nil {
+		return 1
+	}
+	if ws, ok := state.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+	return state.ExitCode()
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func printRunSummary(w io.Writer, cmdArgs []string, state *os.ProcessState, exitCode int, wall time.Duration, stats metrics.RunStats) {
+	userTime := state.UserTime()
+	sysTime := state.SystemTime()
+	cpuShare := 0.0
+	if wall > 0 {
+		cpuShare = (userTime + sysTime).Seconds() / wall.Seconds() * 100
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "── cpu-monitor run summary "+strings.Repeat("─", 40))
+	fmt.Fprintf(w, "Command:      %s\n", strings.Join(cmdArgs, " "))
+	fmt.Fprintf(w, "Exit status:  %d\n", exitCode)
+	fmt.Fprintf(w, "Wall time:    %.2fs\n", wall.Seconds())
+	fmt.Fprintf(w, "CPU time:     user %.2fs  sys %.2fs  (%.0f%% of one CPU)\n",
+		userTime.Seconds(), sysTime.Seconds(), cpuShare)
+	if stats.Samples > 0 {
+		fmt.Fprintf(w, "CPU usage:    peak %.1f%%  avg %.1f%%  (%d samples)\n",
+			stats.PeakCPU, stats.AverageCPU, stats.Samples)
+	} else {
+		// Finished within one refresh: fall back to rusage like time(1)
+		fmt.Fprintf(w, "CPU usage:    avg %.1f%%  (from rusage, exited before the first sample)\n", cpuShare)
+	}
+	// rusage has the largest single waited-for process, sampling the
+	// whole tree at refresh intervals; report whichever saw more
+	if maxRSS := maxRSS(state); maxRSS > stats.PeakRSS {
+		fmt.Fprintf(w, "Peak RSS:     %s (largest process, from rusage)\n", metrics.FormatBytes(maxRSS))
+	} else if stats.PeakRSS > 0 {
+		fmt.Fprintf(w, "Peak RSS:     %s (whole process tree)\n", metrics.FormatBytes(stats.PeakRSS))
+	}
+	if stats.Samples > 0 {
+		fmt.Fprintf(w, "History:      %s\n", plainSparkline(stats.History, 60))
+	}
+
+	if len(stats.Busiest) > 0 {
+		// rusage only counts children that were waited for, so also show
+		// what sampling saw across the whole tree
+		var treeTime float64
+		for _, p := range stats.Busiest {
+			treeTime += p.CPUTime
+		}
+		fmt.Fprintf(w, "Tree CPU:     %.2fs sampled across the busiest %d processes\n", treeTime, len(stats.Busiest))
+		fmt.Fprintln(w, "Busiest processes:")
+		fmt.Fprintf(w, "  %7s %10s %10s  %s\n", "PID", "CPU TIME", "PEAK RSS", "COMMAND")
+		for _, p := range stats.Busiest {
+			command := p.Cmdline
+			if len(command) > 60 {
+				command = command[:57] + "..."
+			}
+			fmt.Fprintf(w, "  %7d %9.2fs %10s  %s\n", p.PID, p.CPUTime, metrics.FormatBytes(p.PeakRSS), command)
+		}
+	}
+}
+
+// plainSparkline draws values without colors so the summary stays
+// readable in CI logs.
+func plainSparkline(values []float64, width int) string {
+	levels := []string{
+		config.GraphBar1, config.GraphBar2, config.GraphBar3, config.GraphBar4,
+		config.GraphBar5, config.GraphBar6, config.GraphBar7, config.GraphBar8,
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	peak := 0.0
+	for _, v := range values {
+		if v > peak {
+			peak = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		if peak <= 0 || v <= 0 {
+			b.WriteString(" ")
+			continue
+		}
+		b.WriteString(levels[int(v/peak*float64(len(levels)-1))])
+	}
+	return b.String()
+}
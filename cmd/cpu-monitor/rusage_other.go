@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// maxRSS is unknown where rusage has no peak resident set size.
+func maxRSS(state *os.ProcessState) uint64 {
+	return 0
+}
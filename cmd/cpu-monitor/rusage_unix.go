@@ -0,0 +1,26 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// maxRSS is the peak resident set size in bytes of the largest process
+// the command's rusage covers, or 0 when unknown.
+func maxRSS(state *os.ProcessState) uint64 {
+	if state == nil {
+		return 0
+	}
+	ru, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok || ru.Maxrss <= 0 {
+		return 0
+	}
+	// ru_maxrss is in bytes on macOS and kilobytes elsewhere
+	if runtime.GOOS == "darwin" {
+		return uint64(ru.Maxrss)
+	}
+	return uint64(ru.Maxrss) * 1024
+}
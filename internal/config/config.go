@@ -1,6 +1,7 @@
 package config
 
 import (
+	"regexp"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
@@ -10,12 +11,29 @@ type Config struct {
 	RefreshRate   time.Duration
 	HistorySize   int
 	MovingAvgSize int
+	// SysfsRoot is where sysfs is mounted; override to read a fake tree
+	SysfsRoot string
+	// ProcfsRoot is where procfs is mounted; override to read fixtures
+	ProcfsRoot string
+	// CgroupRoot is where the cgroup v2 hierarchy is mounted
+	CgroupRoot string
+	// TempSensor selects the sensor shown as CPU temperature (empty = auto)
+	TempSensor string
+	// ScopePIDs and ScopeName narrow the dashboard to matching processes
+	// and their descendants
+	ScopePIDs []int32
+	ScopeName *regexp.Regexp
+	// ScopeLabel describes the scope in the UI instead of the PID list
+	ScopeLabel string
 }
 
 var DefaultConfig = Config{
 	RefreshRate:   500 * time.Millisecond,
 	HistorySize:   120,
 	MovingAvgSize: 10,
+	SysfsRoot:     "/sys",
+	ProcfsRoot:    "/proc",
+	CgroupRoot:    "/sys/fs/cgroup",
 }
 
 type ColorScheme struct {
@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CgroupMetrics describes the cgroup v2 group the monitor runs in, so usage
+// inside a container can be judged against its own limits.
+type CgroupMetrics struct {
+	// Path is the group relative to the cgroupfs root, e.g.
+	// "/kubepods.slice/kubepods-pod1.slice/cri-containerd-ab12.scope"
+	Path string
+	// QuotaCPUs is the tightest cpu.max limit of the group and its
+	// ancestors in CPUs; 0 means unlimited
+	QuotaCPUs float64
+	// CPUsUsed is the group's usage in CPUs since the previous collection,
+	// and Usage is that as a percentage of QuotaCPUs (or of every CPU
+	// when unlimited)
+	CPUsUsed float64
+	Usage    float64
+	// Throttling from cpu.stat: cumulative counts, and the share of
+	// enforcement periods throttled since the previous collection
+	NrPeriods         uint64
+	NrThrottled       uint64
+	ThrottledTime     time.Duration
+	ThrottledPercent  float64
+	ThrottledRecently bool
+	// memory.current, and the tightest memory.max (0 = unlimited)
+	MemoryCurrent uint64
+	MemoryMax     uint64
+	MemoryUsage   float64
+}
+
+// cgroupCPUStat holds the cpu.stat counters used for deltas.
+type cgroupCPUStat struct {
+	usageUsec     uint64
+	nrPeriods     uint64
+	nrThrottled   uint64
+	throttledUsec uint64
+}
+
+// readSelfCgroupV2 returns the unified hierarchy path from a
+// /proc/<pid>/cgroup file, or false on cgroup v1-only systems.
+func readSelfCgroupV2(path string) (string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if rest, ok := strings.CutPrefix(line, "0::"); ok {
+			return rest, true
+		}
+	}
+	return "", false
+}
+
+func readCgroupCPUStat(dir string) (cgroupCPUStat, error) {
+	values, err := readVMStat(filepath.Join(dir, "cpu.stat"),
+		"usage_usec", "nr_periods", "nr_throttled", "throttled_usec")
+	if err != nil {
+		return cgroupCPUStat{}, err
+	}
+	return cgroupCPUStat{
+		usageUsec:     values["usage_usec"],
+		nrPeriods:     values["nr_periods"],
+		nrThrottled:   values["nr_throttled"],
+		throttledUsec: values["throttled_usec"],
+	}, nil
+}
+
+// readCPUMax parses cpu.max ("$MAX $PERIOD", MAX may be "max") into CPUs.
+func readCPUMax(dir string) float64 {
+	s, err := readString(filepath.Join(dir, "cpu.max"))
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(s)
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0
+	}
+	quota, err1 := strconv.ParseFloat(fields[0], 64)
+	period, err2 := strconv.ParseFloat(fields[1], 64)
+	if err1 != nil || err2 != nil || period <= 0 {
+		return 0
+	}
+	return quota / period
+}
+
+// readMemoryMax parses memory.max, which is "max" when unlimited.
+func readMemoryMax(dir string) uint64 {
+	v, err := readUint(filepath.Join(dir, "memory.max"))
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// effectiveCgroupLimits walks from dir up to root and returns the tightest
+// CPU and memory limits, since any ancestor's limit applies to the group.
+func effectiveCgroupLimits(root, dir string) (cpus float64, memMax uint64) {
+	for {
+		if q := readCPUMax(dir); q > 0 && (cpus == 0 || q < cpus) {
+			cpus = q
+		}
+		if mm := readMemoryMax(dir); mm > 0 && (memMax == 0 || mm < memMax) {
+			memMax = mm
+		}
+		if dir == root || len(dir) <= len(root) {
+			return cpus, memMax
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// isCgroupNamespaceRoot reports whether the cgroupfs root is a container's
+// own group seen through a cgroup namespace: the host root has no cpu.max
+// or memory.max, a delegated group does.
+func isCgroupNamespaceRoot(root string) bool {
+	for _, name := range []string{"cpu.max", "memory.max"} {
+		if _, err := os.Stat(filepath.Join(root, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// collectCgroup reads the monitor's own cgroup v2 group. It returns nil on
+// cgroup v1 hosts and when running in the host's root group, where the
+// figures would just repeat the system-wide ones. Inside a cgroup
+// namespace (Docker, Kubernetes) the group shows up as "/", and the
+// cgroupfs root is the container's group.
+func (c *Collector) collectCgroup(numCPU int) *CgroupMetrics {
+	path, ok := readSelfCgroupV2(filepath.Join(c.opts.ProcfsRoot, "self", "cgroup"))
+	if !ok || path == "" {
+		return nil
+	}
+	root := filepath.Clean(c.opts.CgroupRoot)
+	if path == "/" && !isCgroupNamespaceRoot(root) {
+		return nil
+	}
+	dir := filepath.Join(root, path)
+
+	now := time.Now()
+	stat, err := readCgroupCPUStat(dir)
+	if err != nil {
+		return nil
+	}
+
+	cg := &CgroupMetrics{
+		Path:          path,
+		NrPeriods:     stat.nrPeriods,
+		NrThrottled:   stat.nrThrottled,
+		ThrottledTime: time.Duration(stat.throttledUsec) * time.Microsecond,
+	}
+	cg.QuotaCPUs, cg.MemoryMax = effectiveCgroupLimits(root, dir)
+
+	// Forget the previous sample if we were moved to another group
+	if c.lastCgroupPath == path && !c.lastCgroupTime.IsZero() {
+		prev := c.lastCgroupStat
+		elapsed := now.Sub(c.lastCgroupTime).Seconds()
+		cg.CPUsUsed = counterRate(prev.usageUsec, stat.usageUsec, elapsed) / 1e6
+		capacity := cg.QuotaCPUs
+		if capacity == 0 {
+			capacity = float64(numCPU)
+		}
+		if capacity > 0 {
+			cg.Usage = cg.CPUsUsed / capacity * 100
+		}
+		if stat.nrPeriods > prev.nrPeriods && stat.nrThrottled >= prev.nrThrottled {
+			cg.ThrottledPercent = float64(stat.nrThrottled-prev.nrThrottled) / float64(stat.nrPeriods-prev.nrPeriods) * 100
+		}
+		cg.ThrottledRecently = stat.nrThrottled > prev.nrThrottled
+	}
+	c.lastCgroupPath = path
+	c.lastCgroupStat = stat
+	c.lastCgroupTime = now
+
+	if current, err := readUint(filepath.Join(dir, "memory.current")); err == nil {
+		cg.MemoryCurrent = current
+		if cg.MemoryMax > 0 {
+			cg.MemoryUsage = float64(current) / float64(cg.MemoryMax) * 100
+		}
+	}
+
+	return cg
+}
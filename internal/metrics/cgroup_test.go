@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadCPUMax(t *testing.T) {
+	tests := []struct {
+		content string
+		want    float64
+	}{
+		{"150000 100000\n", 1.5},
+		{"50000 100000\n", 0.5},
+		{"max 100000\n", 0},
+		{"100000\n", 0},
+		{"100000 0\n", 0},
+		{"abc 100000\n", 0},
+	}
+	for _, tt := range tests {
+		dir := writeTree(t, map[string]string{"cpu.max": tt.content})
+		if got := readCPUMax(dir); got != tt.want {
+			t.Errorf("readCPUMax(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+	if got := readCPUMax(t.TempDir()); got != 0 {
+		t.Errorf("readCPUMax without cpu.max = %v, want 0", got)
+	}
+}
+
+func TestEffectiveCgroupLimits(t *testing.T) {
+	// The pod limits CPU, the container memory; the root has no limit files
+	root := writeTree(t, map[string]string{
+		"kubepods.slice/cpu.max":                   "max 100000\n",
+		"kubepods.slice/memory.max":                "max\n",
+		"kubepods.slice/pod1/cpu.max":              "200000 100000\n",
+		"kubepods.slice/pod1/memory.max":           "max\n",
+		"kubepods.slice/pod1/container/cpu.max":    "max 100000\n",
+		"kubepods.slice/pod1/container/memory.max": "536870912\n",
+	})
+	cpus, memMax := effectiveCgroupLimits(root, filepath.Join(root, "kubepods.slice", "pod1", "container"))
+	if cpus != 2 || memMax != 536870912 {
+		t.Errorf("effectiveCgroupLimits = %v CPUs, %d bytes; want 2, 536870912", cpus, memMax)
+	}
+
+	cpus, memMax = effectiveCgroupLimits(root, filepath.Join(root, "kubepods.slice"))
+	if cpus != 0 || memMax != 0 {
+		t.Errorf("effectiveCgroupLimits(unlimited) = %v, %d; want 0, 0", cpus, memMax)
+	}
+}
+
+func TestCollectCgroupThrottling(t *testing.T) {
+	procfs := writeTree(t, map[string]string{"self/cgroup": "0::/app.slice\n"})
+	cgroupfs := writeTree(t, map[string]string{
+		"app.slice/cpu.max":        "50000 100000\n",
+		"app.slice/memory.max":     "1000\n",
+		"app.slice/memory.current": "250\n",
+		"app.slice/cpu.stat": "usage_usec 1000000\nnr_periods 100\nnr_throttled 10\n" +
+			"throttled_usec 500000\n",
+	})
+	c := NewCollector(Options{ProcfsRoot: procfs, SysfsRoot: t.TempDir(), CgroupRoot: cgroupfs})
+
+	cg := c.collectCgroup(4)
+	if cg == nil {
+		t.Fatal("collectCgroup = nil, want the app.slice group")
+	}
+	if cg.Path != "/app.slice" || cg.QuotaCPUs != 0.5 || cg.MemoryUsage != 25 {
+		t.Errorf("first collection: path %q, quota %v, memory %v%%; want /app.slice, 0.5, 25%%",
+			cg.Path, cg.QuotaCPUs, cg.MemoryUsage)
+	}
+	if cg.ThrottledPercent != 0 || cg.ThrottledRecently || cg.ThrottledTime != 500*time.Millisecond {
+		t.Errorf("first collection should only report totals, got %+v", cg)
+	}
+
+	// 40 more periods, 10 of them throttled, and 0.25s of CPU
+	if err := os.WriteFile(filepath.Join(cgroupfs, "app.slice", "cpu.stat"), []byte(
+		"usage_usec 1250000\nnr_periods 140\nnr_throttled 20\nthrottled_usec 600000\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c.lastCgroupTime = time.Now().Add(-time.Second)
+	cg = c.collectCgroup(4)
+	if cg.ThrottledPercent != 25 || !cg.ThrottledRecently {
+		t.Errorf("throttled %v%% recently %v; want 25%%, true", cg.ThrottledPercent, cg.ThrottledRecently)
+	}
+	if cg.CPUsUsed < 0.2 || cg.CPUsUsed > 0.25 || cg.Usage < 40 || cg.Usage > 50 {
+		t.Errorf("CPUsUsed %v, Usage %v%%; want about 0.25 CPUs, 50%% of the quota", cg.CPUsUsed, cg.Usage)
+	}
+
+	// A counter reset (group recreated) must not report throttling
+	if err := os.WriteFile(filepath.Join(cgroupfs, "app.slice", "cpu.stat"), []byte(
+		"usage_usec 10\nnr_periods 1\nnr_throttled 0\nthrottled_usec 0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cg = c.collectCgroup(4)
+	if cg.ThrottledPercent != 0 || cg.ThrottledRecently || cg.CPUsUsed != 0 {
+		t.Errorf("after a reset: throttled %v%%, recently %v, CPUs %v; want zeros",
+			cg.ThrottledPercent, cg.ThrottledRecently, cg.CPUsUsed)
+	}
+}
+
+func TestCollectCgroupNamespaceRoot(t *testing.T) {
+	procfs := writeTree(t, map[string]string{"self/cgroup": "0::/\n"})
+	cpuStat := "usage_usec 0\nnr_periods 0\nnr_throttled 0\nthrottled_usec 0\n"
+
+	// The host root group has no limit files: nothing to add
+	host := writeTree(t, map[string]string{"cpu.stat": cpuStat})
+	c := NewCollector(Options{ProcfsRoot: procfs, SysfsRoot: t.TempDir(), CgroupRoot: host})
+	if cg := c.collectCgroup(4); cg != nil {
+		t.Errorf("host root group: collectCgroup = %+v, want nil", cg)
+	}
+
+	// In a cgroup namespace the root is the container's own group
+	container := writeTree(t, map[string]string{
+		"cpu.stat":   cpuStat,
+		"cpu.max":    "100000 100000\n",
+		"memory.max": "max\n",
+	})
+	c = NewCollector(Options{ProcfsRoot: procfs, SysfsRoot: t.TempDir(), CgroupRoot: container})
+	cg := c.collectCgroup(4)
+	if cg == nil || cg.Path != "/" || cg.QuotaCPUs != 1 {
+		t.Errorf("namespace root: collectCgroup = %+v, want path / with 1 CPU quota", cg)
+	}
+}
@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCgroupDepth limits how deep CgroupCollector walks below the root, like
+// systemd-cgtop; deeper groups are still counted in their ancestors.
+const maxCgroupDepth = 3
+
+// CgroupUsage is the resource use of one cgroup v2 group.
+type CgroupUsage struct {
+	// Path is relative to the cgroupfs root, e.g. "system.slice/nginx.service"
+	Path string
+	// Name is a readable form of the last path element
+	Name  string
+	Depth int
+	// CPUPercent is usage since the previous collection (100 = one CPU)
+	CPUPercent    float64
+	MemoryCurrent uint64
+	// ThrottledPercent is the share of quota periods throttled since the
+	// previous collection
+	ThrottledPercent float64
+	NrThrottled      uint64
+}
+
+// CgroupCollector ranks every cgroup v2 group by CPU usage. It is separate
+// from Collector since walking the hierarchy is only worth doing while the
+// cgroups screen is open.
+type CgroupCollector struct {
+	mu       sync.Mutex
+	root     string
+	last     map[string]cgroupCPUStat
+	lastTime time.Time
+}
+
+func NewCgroupCollector(root string) *CgroupCollector {
+	if root == "" {
+		root = DefaultCgroupRoot
+	}
+	return &CgroupCollector{
+		root: filepath.Clean(root),
+		last: make(map[string]cgroupCPUStat),
+	}
+}
+
+// Collect walks the hierarchy and returns the groups sorted by CPU usage,
+// busiest first. Rates are zero on the first call.
+func (c *CgroupCollector) Collect() ([]CgroupUsage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(c.lastTime).Seconds()
+	first := c.lastTime.IsZero()
+	stats := make(map[string]cgroupCPUStat, len(c.last))
+	var groups []CgroupUsage
+
+	err := filepath.WalkDir(c.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Groups can vanish mid-walk; skip what we can't read
+			if path == c.root {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() || path == c.root {
+			return nil
+		}
+		rel, err := filepath.Rel(c.root, path)
+		if err != nil {
+			return nil
+		}
+		depth := strings.Count(rel, string(filepath.Separator)) + 1
+		if depth > maxCgroupDepth {
+			return fs.SkipDir
+		}
+
+		stat, err := readCgroupCPUStat(path)
+		if err != nil {
+			return nil
+		}
+		stats[rel] = stat
+
+		g := CgroupUsage{
+			Path:        rel,
+			Name:        CgroupDisplayName(d.Name()),
+			Depth:       depth,
+			NrThrottled: stat.nrThrottled,
+		}
+		if prev, ok := c.last[rel]; ok && !first {
+			g.CPUPercent = counterRate(prev.usageUsec, stat.usageUsec, elapsed) / 1e6 * 100
+			if stat.nrPeriods > prev.nrPeriods && stat.nrThrottled >= prev.nrThrottled {
+				g.ThrottledPercent = float64(stat.nrThrottled-prev.nrThrottled) / float64(stat.nrPeriods-prev.nrPeriods) * 100
+			}
+		}
+		if current, err := readUint(filepath.Join(path, "memory.current")); err == nil {
+			g.MemoryCurrent = current
+		}
+		groups = append(groups, g)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.last = stats
+	c.lastTime = now
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].CPUPercent != groups[j].CPUPercent {
+			return groups[i].CPUPercent > groups[j].CPUPercent
+		}
+		return groups[i].Path < groups[j].Path
+	})
+	return groups, nil
+}
+
+// Container runtime scopes embed a 64-hex-digit container ID
+var containerScopeRe = regexp.MustCompile(`^(?:(docker|libpod|crio|cri-containerd)-)?([0-9a-f]{64})(?:\.scope)?$`)
+
+// Kubernetes pod slices, e.g. kubepods-burstable-pod<uid>.slice
+var podSliceRe = regexp.MustCompile(`^kubepods(?:-(besteffort|burstable))?-pod([0-9a-f_]+)\.slice$`)
+
+var containerRuntimes = map[string]string{
+	"":               "container",
+	"docker":         "docker",
+	"libpod":         "podman",
+	"crio":           "cri-o",
+	"cri-containerd": "containerd",
+}
+
+// CgroupDisplayName turns a cgroup directory name into something readable:
+// "nginx.service" becomes "nginx", "docker-<id>.scope" becomes
+// "docker <short id>", and pod slices show their QoS class and UID prefix.
+func CgroupDisplayName(name string) string {
+	if m := containerScopeRe.FindStringSubmatch(name); m != nil {
+		return containerRuntimes[m[1]] + " " + m[2][:12]
+	}
+	if m := podSliceRe.FindStringSubmatch(name); m != nil {
+		uid := strings.ReplaceAll(m[2], "_", "-")
+		if len(uid) > 8 {
+			uid = uid[:8]
+		}
+		if m[1] != "" {
+			return "pod " + uid + " (" + m[1] + ")"
+		}
+		return "pod " + uid
+	}
+	if rest, ok := strings.CutPrefix(name, "user-"); ok && strings.HasSuffix(rest, ".slice") {
+		return "user " + strings.TrimSuffix(rest, ".slice")
+	}
+	if rest, ok := strings.CutPrefix(name, "session-"); ok && strings.HasSuffix(rest, ".scope") {
+		return "session " + strings.TrimSuffix(rest, ".scope")
+	}
+	for _, suffix := range []string{".service", ".scope", ".slice"} {
+		if strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+			return strings.TrimSuffix(name, suffix)
+		}
+	}
+	return name
+}
@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCgroupDisplayName(t *testing.T) {
+	const id = "4f3c2b1a0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b"
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"nginx.service", "nginx"},
+		{"system.slice", "system"},
+		{"init.scope", "init"},
+		{"user-1000.slice", "user 1000"},
+		{"session-3.scope", "session 3"},
+		{"user@1000.service", "user@1000"},
+		{"docker-" + id + ".scope", "docker 4f3c2b1a0e9d"},
+		{"libpod-" + id + ".scope", "podman 4f3c2b1a0e9d"},
+		{"cri-containerd-" + id + ".scope", "containerd 4f3c2b1a0e9d"},
+		{id, "container 4f3c2b1a0e9d"},
+		{"kubepods-burstable-pod0a1b2c3d_4e5f_6789_abcd_ef0123456789.slice", "pod 0a1b2c3d (burstable)"},
+		{"kubepods-pod0a1b2c3d_4e5f.slice", "pod 0a1b2c3d"},
+		{".service", ".service"},
+		{"machine", "machine"},
+	}
+	for _, tt := range tests {
+		if got := CgroupDisplayName(tt.name); got != tt.want {
+			t.Errorf("CgroupDisplayName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCgroupCollector(t *testing.T) {
+	cpuStat := func(usageUsec, periods, throttled string) string {
+		return "usage_usec " + usageUsec + "\nnr_periods " + periods + "\nnr_throttled " + throttled + "\nthrottled_usec 0\n"
+	}
+	root := writeTree(t, map[string]string{
+		// The root group's own files are not a group of their own
+		"cpu.stat": cpuStat("999999999", "0", "0"),
+
+		"system.slice/cpu.stat":                     cpuStat("3000000", "0", "0"),
+		"system.slice/nginx.service/cpu.stat":       cpuStat("2000000", "100", "10"),
+		"system.slice/nginx.service/memory.current": "4096\n",
+		"user.slice/cpu.stat":                       cpuStat("1000000", "0", "0"),
+		"user.slice/user-1000.slice/cpu.stat":       cpuStat("1000000", "0", "0"),
+		// Depth 3 is shown, depth 4 only counts toward its ancestors
+		"user.slice/user-1000.slice/session-2.scope/cpu.stat":        cpuStat("500000", "0", "0"),
+		"user.slice/user-1000.slice/session-2.scope/deeper/cpu.stat": cpuStat("100000", "0", "0"),
+		// Directories without cpu.stat (no cpu controller) are skipped
+		"init.scope/cgroup.procs": "1\n",
+	})
+
+	c := NewCgroupCollector(root)
+	groups, err := c.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	byPath := make(map[string]CgroupUsage)
+	for _, g := range groups {
+		byPath[filepath.ToSlash(g.Path)] = g
+		if g.Depth > maxCgroupDepth {
+			t.Errorf("%s at depth %d, beyond the cap of %d", g.Path, g.Depth, maxCgroupDepth)
+		}
+		if g.CPUPercent != 0 {
+			t.Errorf("%s: first collection reported %v%% CPU", g.Path, g.CPUPercent)
+		}
+	}
+	want := map[string]struct {
+		name  string
+		depth int
+	}{
+		"system.slice":                               {"system", 1},
+		"system.slice/nginx.service":                 {"nginx", 2},
+		"user.slice":                                 {"user", 1},
+		"user.slice/user-1000.slice":                 {"user 1000", 2},
+		"user.slice/user-1000.slice/session-2.scope": {"session 2", 3},
+	}
+	if len(byPath) != len(want) {
+		t.Errorf("got groups %v, want %d groups", slices.Sorted(maps.Keys(byPath)), len(want))
+	}
+	for path, w := range want {
+		g, ok := byPath[path]
+		if !ok {
+			t.Errorf("missing group %s", path)
+			continue
+		}
+		if g.Name != w.name || g.Depth != w.depth {
+			t.Errorf("%s: name %q depth %d, want %q depth %d", path, g.Name, g.Depth, w.name, w.depth)
+		}
+	}
+	if g := byPath["system.slice/nginx.service"]; g.MemoryCurrent != 4096 || g.NrThrottled != 10 {
+		t.Errorf("nginx: memory %d, throttled %d; want 4096, 10", g.MemoryCurrent, g.NrThrottled)
+	}
+
+	// One second later nginx used half a CPU and was throttled in 5 of 20 periods
+	nginx := filepath.Join(root, "system.slice", "nginx.service", "cpu.stat")
+	if err := os.WriteFile(nginx, []byte(cpuStat("2500000", "120", "15")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c.lastTime = time.Now().Add(-time.Second)
+	groups, err = c.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	top := groups[0]
+	if filepath.ToSlash(top.Path) != "system.slice/nginx.service" || top.CPUPercent < 45 || top.CPUPercent > 50 {
+		t.Errorf("busiest = %s at %.1f%%, want nginx at about 50%%", top.Path, top.CPUPercent)
+	}
+	if top.ThrottledPercent != 25 {
+		t.Errorf("nginx throttled %v%% of periods, want 25%%", top.ThrottledPercent)
+	}
+}
@@ -1,130 +1,363 @@
 package metrics
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"sync"
 	"time"
 
+	"github.com/shirou/gopsutil/v3/common"
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/host"
 	"github.com/shirou/gopsutil/v3/load"
 	"github.com/shirou/gopsutil/v3/mem"
-	"github.com/shirou/gopsutil/v3/process"
 )
 
 type CPUMetrics struct {
-	TotalUsage    float64
-	PerCoreUsage  []float64
-	Temperature   float64
-	Frequency     float64
-	ModelName     string
-	CoreCount     int
-	ThreadCount   int
-	ProcessCount  int
-	LoadAverage   [3]float64
-	MemoryUsage   float64
-	MemoryTotal   uint64
-	MemoryUsed    uint64
-	Uptime        time.Duration
-	Timestamp     time.Time
+	TotalUsage   float64
+	PerCoreUsage []float64
+	// CPUIDs holds the kernel CPU number of each PerCoreUsage entry. Only
+	// online CPUs are listed; OfflineCPUs are present but offline
+	CPUIDs      []int
+	OfflineCPUs []int
+	// Per-state time shares for the whole system and for each core
+	TotalBreakdown   CPUBreakdown
+	PerCoreBreakdown []CPUBreakdown
+	Temperature      float64
+	// Every temperature sensor, the key of the one used as Temperature,
+	// and per-core temperatures aligned with PerCoreUsage
+	Sensors            []SensorReading
+	PackageSensor      string
+	PerCoreTemperature []float64
+	// MissingSensor is the configured TempSensor when no sensor has that
+	// key and PackageSensor was picked automatically instead
+	MissingSensor string
+	// ThermalZones are the kernel's thermal zones, refreshed with the
+	// sensors; Throttle counts thermal throttling of the CPUs
+	ThermalZones []ThermalZone
+	Throttle     ThrottleMetrics
+	Frequency    float64
+	// Current frequency of each core in MHz (0 when unavailable)
+	PerCoreFrequency []float64
+	ModelName        string
+	// Physical cores, logical CPUs, sockets and NUMA nodes
+	CoreCount   int
+	ThreadCount int
+	SocketCount int
+	NodeCount   int
+	// Topology places each logical CPU, aligned with PerCoreUsage
+	Topology     []CPUTopology
+	ProcessCount int
+	// Processes is refreshed every 2 seconds; CPU usage covers that span
+	Processes []ProcessInfo
+	// ProcessTimestamp is when Processes was last refreshed
+	ProcessTimestamp time.Time
+	// Scope aggregates the monitored processes; nil when monitoring the
+	// whole system
+	Scope       *ScopeMetrics
+	LoadAverage [3]float64
+	MemoryUsage float64
+	MemoryTotal uint64
+	MemoryUsed  uint64
+	// Memory is the detailed RAM breakdown behind the figures above
+	Memory MemoryMetrics
+	// Swap space and paging activity; rates are per second since the
+	// previous collection and swap rates are in bytes
+	SwapTotal      uint64
+	SwapUsed       uint64
+	SwapFree       uint64
+	SwapUsage      float64
+	SwapInRate     float64
+	SwapOutRate    float64
+	MajorFaultRate float64
+	// Scheduler activity from /proc/stat: per-second rates of context
+	// switches, interrupts and forks, and tasks currently runnable or
+	// blocked on I/O
+	ContextSwitchRate float64
+	InterruptRate     float64
+	ForkRate          float64
+	ProcsRunning      uint64
+	ProcsBlocked      uint64
+	// Interrupts holds per-CPU rates of every hardware IRQ and softirq,
+	// busiest first; InterruptCPUs numbers their columns
+	Interrupts    []IRQRate
+	InterruptCPUs []int
+	// Disks is per-device I/O activity for whole block devices, sorted
+	// by name
+	Disks []DiskStats
+	// Network is per-interface traffic, sorted by name
+	Network []NetStats
+	// Cgroup is the cgroup v2 group the monitor runs in; nil outside a
+	// non-root v2 group
+	Cgroup *CgroupMetrics
+	// Pressure is the kernel's stall information, read from
+	// <procfs>/pressure
+	Pressure  PressureMetrics
+	Uptime    time.Duration
+	Timestamp time.Time
 }
 
+// Options configures where a Collector reads kernel data from.
+type Options struct {
+	// SysfsRoot is the mount point of sysfs (default "/sys")
+	SysfsRoot string
+	// ProcfsRoot is the mount point of procfs (default "/proc")
+	ProcfsRoot string
+	// CgroupRoot is the mount point of the cgroup v2 hierarchy (default
+	// "/sys/fs/cgroup")
+	CgroupRoot string
+	// TempSensor is the sensor key to report as the CPU package
+	// temperature; empty picks a well-known CPU sensor automatically
+	TempSensor string
+	// Scope limits the scoped figures to some processes; the process
+	// list is then refreshed on every collection
+	Scope Scope
+}
+
+// Collector gathers system metrics. Collect may block on slow sources, so
+// callers should run it off the UI goroutine; calls are serialized.
 type Collector struct {
-	lastPerCPU []float64
+	mu   sync.Mutex
+	opts Options
+	// ctx carries the sysfs and procfs roots to gopsutil
+	ctx context.Context
+	// Previous per-CPU times, keyed by CPU name, for delta-based usage
+	lastTimes map[string]cpu.TimesStat
 	// Cached static values
-	cpuModelName string
-	coreCount    int
-	threadCount  int
+	cpuModelName  string
+	baseFrequency float64
+	coreCount     int
+	threadCount   int
+	// CPU names of the last collection in cpu.Times order, and their
+	// topology, re-read when the names change
+	cpuNames []string
+	topology []CPUTopology
 	// Throttle expensive operations
 	lastProcessUpdate time.Time
 	processCount      int
-	lastTempUpdate    time.Time
-	temperature       float64
+	processes         []ProcessInfo
+	processTracker    *processTracker
+	processTimestamp  time.Time
+	// Previous /proc/vmstat paging counters
+	lastVMStat     map[string]uint64
+	lastVMStatTime time.Time
+	// time_in_state totals from the last CollectFrequencyDetail
+	lastTimeInState map[uint64]uint64
+	// cpuidle counters per CPU name from the last CollectIdleDetail
+	lastIdle     map[string][]idleCounters
+	lastIdleTime time.Time
+	// Previous thermal_throttle counters
+	lastThrottle throttleCounters
+	// Previous /proc/stat scheduler counters
+	lastProcStat     procStatCounters
+	lastProcStatTime time.Time
+	// Previous per-CPU interrupt counts, keyed by irqKey
+	lastIRQ     map[string][]uint64
+	lastIRQTime time.Time
+	// Previous /proc/diskstats counters, keyed by device
+	lastDiskStats map[string]diskCounters
+	lastDiskTime  time.Time
+	// Previous /proc/net/dev counters, keyed by interface
+	lastNetStats map[string]netCounters
+	lastNetTime  time.Time
+	// Previous cpu.stat of our own cgroup
+	lastCgroupPath string
+	lastCgroupStat cgroupCPUStat
+	lastCgroupTime time.Time
+	// Thread CPU times of the process last passed to CollectProcessDetail
+	threadSample     threadSample
+	lastTempUpdate   time.Time
+	temperature      float64
+	packageSensor    string
+	missingSensor    string
+	sensors          []SensorReading
+	coreTemperatures []float64
+	thermalZones     []ThermalZone
 }
 
-func NewCollector() *Collector {
+func NewCollector(opts Options) *Collector {
+	if opts.SysfsRoot == "" {
+		opts.SysfsRoot = DefaultSysfsRoot
+	}
+	if opts.ProcfsRoot == "" {
+		opts.ProcfsRoot = DefaultProcfsRoot
+	}
+	if opts.CgroupRoot == "" {
+		opts.CgroupRoot = DefaultCgroupRoot
+	}
+
 	c := &Collector{
-		threadCount: runtime.NumCPU(),
+		opts: opts,
+		ctx: context.WithValue(context.Background(), common.EnvKey, common.EnvMap{
+			common.HostSysEnvKey:  opts.SysfsRoot,
+			common.HostProcEnvKey: opts.ProcfsRoot,
+		}),
+		threadCount:    runtime.NumCPU(),
+		lastTimes:      make(map[string]cpu.TimesStat),
+		processTracker: newProcessTracker(),
 	}
-	
+
 	// Cache static CPU info
-	if cpuInfo, err := cpu.Info(); err == nil && len(cpuInfo) > 0 {
+	if cpuInfo, err := cpu.InfoWithContext(c.ctx); err == nil && len(cpuInfo) > 0 {
 		c.cpuModelName = cpuInfo[0].ModelName
 		c.coreCount = int(cpuInfo[0].Cores)
+		c.baseFrequency = cpuInfo[0].Mhz
 	}
-	
+
 	return c
 }
 
 func (c *Collector) Collect() (*CPUMetrics, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	metrics := &CPUMetrics{
 		Timestamp: time.Now(),
 	}
 
-	// Get per-core CPU usage with 100ms sampling interval (single call for efficiency)
-	perCorePercent, err := cpu.Percent(100*time.Millisecond, true)
-	if err == nil && len(perCorePercent) > 0 {
-		metrics.PerCoreUsage = perCorePercent
-		
-		// Calculate total CPU usage from per-core data (average of all cores)
-		var total float64
-		for _, coreUsage := range perCorePercent {
-			total += coreUsage
+	// Compute usage from time deltas since the previous collection so the
+	// figures cover the whole refresh interval without sleeping
+	var cpuNames []string
+	perCoreTimes, err := cpu.TimesWithContext(c.ctx, true)
+	if err == nil && len(perCoreTimes) > 0 {
+		metrics.PerCoreUsage = make([]float64, len(perCoreTimes))
+		metrics.PerCoreBreakdown = make([]CPUBreakdown, len(perCoreTimes))
+		prevTimes := make([]cpu.TimesStat, len(perCoreTimes))
+		cpuNames = make([]string, len(perCoreTimes))
+		metrics.CPUIDs = make([]int, len(perCoreTimes))
+		for i, t := range perCoreTimes {
+			cpuNames[i] = t.CPU
+			if id, ok := cpuNumber(t.CPU); ok {
+				metrics.CPUIDs[i] = id
+			} else {
+				metrics.CPUIDs[i] = i
+			}
+			prev, seen := c.lastTimes[t.CPU]
+			if !seen && len(c.lastTimes) > 0 {
+				// A hot-added CPU: start from now rather than counting
+				// its whole uptime into this interval's total
+				prev = t
+			}
+			prevTimes[i] = prev
+			metrics.PerCoreUsage[i] = usageBetween(prev, t)
+			metrics.PerCoreBreakdown[i] = breakdownBetween(prev, t)
+			c.lastTimes[t.CPU] = t
 		}
-		metrics.TotalUsage = total / float64(len(perCorePercent))
+		prevTotal, curTotal := sumTimes(prevTimes), sumTimes(perCoreTimes)
+		metrics.TotalUsage = usageBetween(prevTotal, curTotal)
+		metrics.TotalBreakdown = breakdownBetween(prevTotal, curTotal)
+
+		metrics.PerCoreFrequency = readCoreFrequencies(c.opts.SysfsRoot, cpuNames)
+		metrics.OfflineCPUs = readOfflineCPUs(c.opts.SysfsRoot)
 	}
 
 	// Use cached static values
 	metrics.ModelName = c.cpuModelName
 	metrics.CoreCount = c.coreCount
 	metrics.ThreadCount = c.threadCount
-	
-	// Get current frequency (this can change)
-	if cpuInfo, err := cpu.Info(); err == nil && len(cpuInfo) > 0 {
-		metrics.Frequency = cpuInfo[0].Mhz
+
+	// cpu.Info reports per logical CPU, so count physical cores from the
+	// sysfs topology instead
+	if len(cpuNames) > 0 {
+		if !slices.Equal(cpuNames, c.cpuNames) {
+			c.topology = readTopology(c.opts.SysfsRoot, cpuNames)
+			c.cpuNames = cpuNames
+		}
+		metrics.Topology = c.topology
+		metrics.SocketCount, metrics.NodeCount, metrics.CoreCount = TopologySummary(c.topology)
+		metrics.ThreadCount = len(cpuNames)
+	}
+
+	// Prefer the live per-core average; fall back to the nominal frequency
+	// where cpufreq isn't available (e.g. macOS, some VMs)
+	if _, _, avg := metrics.FrequencyStats(); avg > 0 {
+		metrics.Frequency = avg
+	} else {
+		metrics.Frequency = c.baseFrequency
 	}
 
-	// Update temperature every 2 seconds
+	// Update temperatures every 2 seconds
 	if time.Since(c.lastTempUpdate) > 2*time.Second {
-		temps, err := host.SensorsTemperatures()
-		if err == nil {
-			for _, temp := range temps {
-				if temp.SensorKey == "coretemp_core_0" || temp.SensorKey == "TC0P" || temp.SensorKey == "CPU" {
-					c.temperature = temp.Temperature
-					break
-				}
-			}
-			if c.temperature == 0 && len(temps) > 0 {
-				c.temperature = temps[0].Temperature
-			}
+		c.sensors = readSensors(c.ctx)
+		c.temperature = 0
+		c.packageSensor = ""
+		if pkg, ok := selectPackageSensor(c.sensors, c.opts.TempSensor); ok {
+			c.temperature = pkg.Temperature
+			c.packageSensor = pkg.Key
+		}
+		c.missingSensor = ""
+		if c.opts.TempSensor != "" && c.packageSensor != c.opts.TempSensor {
+			c.missingSensor = c.opts.TempSensor
 		}
+		c.coreTemperatures = perCoreTemperatures(c.opts.SysfsRoot, cpuNames, c.topology)
+		c.thermalZones = readThermalZones(c.opts.SysfsRoot)
 		c.lastTempUpdate = time.Now()
 	}
 	metrics.Temperature = c.temperature
+	metrics.PackageSensor = c.packageSensor
+	metrics.MissingSensor = c.missingSensor
+	metrics.Sensors = c.sensors
+	metrics.PerCoreTemperature = c.coreTemperatures
+	metrics.ThermalZones = c.thermalZones
+	// Throttle counters are cheap and events are short, so read them on
+	// every collection rather than with the sensors
+	c.collectThrottle(metrics, cpuNames)
 
-	loadAvg, err := load.Avg()
+	loadAvg, err := load.AvgWithContext(c.ctx)
 	if err == nil {
 		metrics.LoadAverage = [3]float64{loadAvg.Load1, loadAvg.Load5, loadAvg.Load15}
 	}
 
-	// Update process count every 3 seconds
-	if time.Since(c.lastProcessUpdate) > 3*time.Second {
-		processes, err := process.Processes()
+	vmStat, err := mem.VirtualMemoryWithContext(c.ctx)
+	if err == nil {
+		metrics.MemoryUsage = vmStat.UsedPercent
+		metrics.MemoryTotal = vmStat.Total
+		metrics.MemoryUsed = vmStat.Used
+		metrics.Memory = memoryMetrics(vmStat)
+	}
+
+	if swap, err := mem.SwapMemoryWithContext(c.ctx); err == nil {
+		metrics.SwapTotal = swap.Total
+		metrics.SwapUsed = swap.Used
+		metrics.SwapFree = swap.Free
+		metrics.SwapUsage = swap.UsedPercent
+	}
+	c.collectPaging(metrics)
+	metrics.Pressure = readPressureMetrics(c.opts.ProcfsRoot)
+	c.collectProcStat(metrics)
+	c.collectInterrupts(metrics)
+	c.collectDisks(metrics)
+	c.collectNetwork(metrics)
+	metrics.Cgroup = c.collectCgroup(len(metrics.PerCoreUsage))
+
+	// Update the process table every 2 seconds, or every collection when
+	// watching specific processes
+	if c.opts.Scope.Active() || time.Since(c.lastProcessUpdate) > 2*time.Second {
+		processes, err := c.processTracker.collect(c.ctx)
 		if err == nil {
+			if metrics.MemoryTotal > 0 {
+				for i := range processes {
+					processes[i].MemPercent = float64(processes[i].RSS) / float64(metrics.MemoryTotal) * 100
+				}
+			}
+			c.processes = processes
 			c.processCount = len(processes)
+			c.processTimestamp = time.Now()
 		}
 		c.lastProcessUpdate = time.Now()
 	}
 	metrics.ProcessCount = c.processCount
-
-	vmStat, err := mem.VirtualMemory()
-	if err == nil {
-		metrics.MemoryUsage = vmStat.UsedPercent
-		metrics.MemoryTotal = vmStat.Total
-		metrics.MemoryUsed = vmStat.Used
+	metrics.Processes = c.processes
+	metrics.ProcessTimestamp = c.processTimestamp
+	if c.opts.Scope.Active() {
+		metrics.Scope = scopeMetrics(c.opts.Scope, c.processes, len(metrics.PerCoreUsage), metrics.MemoryTotal)
 	}
 
-	hostInfo, err := host.Info()
+	hostInfo, err := host.InfoWithContext(c.ctx)
 	if err == nil {
 		metrics.Uptime = time.Duration(hostInfo.Uptime) * time.Second
 	}
@@ -132,6 +365,64 @@ func (c *Collector) Collect() (*CPUMetrics, error) {
 	return metrics, nil
 }
 
+// MemoryMetrics is the RAM breakdown reported by the kernel. Cached
+// includes reclaimable slab, matching gopsutil.
+type MemoryMetrics struct {
+	Total          uint64
+	Used           uint64
+	Free           uint64
+	Available      uint64
+	Buffers        uint64
+	Cached         uint64
+	Shared         uint64
+	Slab           uint64
+	SReclaimable   uint64
+	Dirty          uint64
+	Writeback      uint64
+	HugePagesTotal uint64
+	HugePagesFree  uint64
+	HugePageSize   uint64
+}
+
+func memoryMetrics(vm *mem.VirtualMemoryStat) MemoryMetrics {
+	return MemoryMetrics{
+		Total:          vm.Total,
+		Used:           vm.Used,
+		Free:           vm.Free,
+		Available:      vm.Available,
+		Buffers:        vm.Buffers,
+		Cached:         vm.Cached,
+		Shared:         vm.Shared,
+		Slab:           vm.Slab,
+		SReclaimable:   vm.Sreclaimable,
+		Dirty:          vm.Dirty,
+		Writeback:      vm.WriteBack,
+		HugePagesTotal: vm.HugePagesTotal,
+		HugePagesFree:  vm.HugePagesFree,
+		HugePageSize:   vm.HugePageSize,
+	}
+}
+
+// collectPaging computes swap-in/out and major fault rates from the
+// /proc/vmstat counters (swap counters are in pages).
+func (c *Collector) collectPaging(metrics *CPUMetrics) {
+	now := time.Now()
+	vmstat, err := readVMStat(filepath.Join(c.opts.ProcfsRoot, "vmstat"), "pswpin", "pswpout", "pgmajfault")
+	if err != nil {
+		return
+	}
+
+	if c.lastVMStat != nil {
+		elapsed := now.Sub(c.lastVMStatTime).Seconds()
+		pageSize := float64(os.Getpagesize())
+		metrics.SwapInRate = counterRate(c.lastVMStat["pswpin"], vmstat["pswpin"], elapsed) * pageSize
+		metrics.SwapOutRate = counterRate(c.lastVMStat["pswpout"], vmstat["pswpout"], elapsed) * pageSize
+		metrics.MajorFaultRate = counterRate(c.lastVMStat["pgmajfault"], vmstat["pgmajfault"], elapsed)
+	}
+	c.lastVMStat = vmstat
+	c.lastVMStatTime = now
+}
+
 func FormatBytes(bytes uint64) string {
 	const unit = 1024
 	if bytes < unit {
@@ -142,9 +433,9 @@ func FormatBytes(bytes uint64) string {
 		div *= unit
 		exp++
 	}
-	units := []string{"B", "KB", "MB", "GB", "TB"}
+	units := []string{"KB", "MB", "GB", "TB", "PB"}
 	if exp >= len(units) {
 		exp = len(units) - 1
 	}
 	return fmt.Sprintf("%.1f %s", float64(bytes)/float64(div), units[exp])
-}
\ No newline at end of file
+}
@@ -0,0 +1,205 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cpuSysfsDir returns the sysfs directory for a CPU name such as "cpu3".
+func cpuSysfsDir(sysfsRoot, cpuName string) string {
+	return filepath.Join(sysfsRoot, "devices", "system", "cpu", cpuName)
+}
+
+// readCoreFrequencies reads scaling_cur_freq for each named CPU and returns
+// the values in MHz. CPUs without cpufreq support report 0.
+func readCoreFrequencies(sysfsRoot string, cpuNames []string) []float64 {
+	freqs := make([]float64, len(cpuNames))
+	for i, name := range cpuNames {
+		khz, err := readUint(filepath.Join(cpuSysfsDir(sysfsRoot, name), "cpufreq", "scaling_cur_freq"))
+		if err == nil {
+			freqs[i] = float64(khz) / 1000
+		}
+	}
+	return freqs
+}
+
+// FrequencyStats returns the lowest, highest and average per-core frequency
+// in MHz, ignoring cores that did not report one.
+func (m *CPUMetrics) FrequencyStats() (min, max, avg float64) {
+	var sum float64
+	var count int
+	for _, f := range m.PerCoreFrequency {
+		if f <= 0 {
+			continue
+		}
+		if count == 0 || f < min {
+			min = f
+		}
+		if f > max {
+			max = f
+		}
+		sum += f
+		count++
+	}
+	if count > 0 {
+		avg = sum / float64(count)
+	}
+	return min, max, avg
+}
+
+// CPUFreqPolicy is the cpufreq configuration of one CPU. Frequencies are
+// in MHz; zero means the file is missing.
+type CPUFreqPolicy struct {
+	CPU      int
+	Driver   string
+	Governor string
+	// EPP is the energy_performance_preference (intel_pstate and
+	// amd-pstate only)
+	EPP         string
+	Current     float64
+	ScalingMin  float64
+	ScalingMax  float64
+	HardwareMin float64
+	HardwareMax float64
+}
+
+// FrequencyResidency is the share of time spent at one frequency.
+type FrequencyResidency struct {
+	MHz   float64
+	Share float64
+}
+
+// FrequencyDetail is the cpufreq state of every online CPU and a
+// time_in_state histogram summed over all CPUs.
+type FrequencyDetail struct {
+	Policies []CPUFreqPolicy
+	// TimeInState covers the time since the previous call, or since boot
+	// on the first call (TimeInStateSinceBoot). Empty when the driver has
+	// no stats (e.g. intel_pstate in active mode)
+	TimeInState          []FrequencyResidency
+	TimeInStateSinceBoot bool
+}
+
+func readKHzAsMHz(path string) float64 {
+	khz, err := readUint(path)
+	if err != nil {
+		return 0
+	}
+	return float64(khz) / 1000
+}
+
+// readTimeInState parses cpufreq/stats/time_in_state: "<kHz> <time>" per
+// line, with time in 10ms units since boot.
+func readTimeInState(path string) (map[uint64]uint64, error) {
+	s, err := readString(path)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[uint64]uint64)
+	for _, line := range strings.Split(s, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		khz, err1 := strconv.ParseUint(fields[0], 10, 64)
+		t, err2 := strconv.ParseUint(fields[1], 10, 64)
+		if err1 == nil && err2 == nil {
+			values[khz] = t
+		}
+	}
+	return values, nil
+}
+
+// CollectFrequencyDetail reads the cpufreq policy of every online CPU and
+// the time_in_state histogram. It is meant for the frequency screen, so it
+// reads far more files than Collect and is only called while that's open.
+func (c *Collector) CollectFrequencyDetail() *FrequencyDetail {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	detail := &FrequencyDetail{}
+	totals := make(map[uint64]uint64)
+	cpus := c.onlineCPUNames()
+	for _, name := range cpus {
+		dir := filepath.Join(cpuSysfsDir(c.opts.SysfsRoot, name), "cpufreq")
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		id, _ := cpuNumber(name)
+		p := CPUFreqPolicy{
+			CPU:         id,
+			Current:     readKHzAsMHz(filepath.Join(dir, "scaling_cur_freq")),
+			ScalingMin:  readKHzAsMHz(filepath.Join(dir, "scaling_min_freq")),
+			ScalingMax:  readKHzAsMHz(filepath.Join(dir, "scaling_max_freq")),
+			HardwareMin: readKHzAsMHz(filepath.Join(dir, "cpuinfo_min_freq")),
+			HardwareMax: readKHzAsMHz(filepath.Join(dir, "cpuinfo_max_freq")),
+		}
+		p.Driver, _ = readString(filepath.Join(dir, "scaling_driver"))
+		p.Governor, _ = readString(filepath.Join(dir, "scaling_governor"))
+		p.EPP, _ = readString(filepath.Join(dir, "energy_performance_preference"))
+		detail.Policies = append(detail.Policies, p)
+
+		if values, err := readTimeInState(filepath.Join(dir, "stats", "time_in_state")); err == nil {
+			for khz, t := range values {
+				totals[khz] += t
+			}
+		}
+	}
+
+	sort.Slice(detail.Policies, func(i, j int) bool {
+		return detail.Policies[i].CPU < detail.Policies[j].CPU
+	})
+
+	// Prefer the interval since the last call; fall back to since boot
+	window := totals
+	detail.TimeInStateSinceBoot = true
+	if c.lastTimeInState != nil {
+		delta := make(map[uint64]uint64, len(totals))
+		var sum uint64
+		for khz, t := range totals {
+			if prev := c.lastTimeInState[khz]; t > prev {
+				delta[khz] = t - prev
+				sum += t - prev
+			}
+		}
+		if sum > 0 {
+			window = delta
+			detail.TimeInStateSinceBoot = false
+		}
+	}
+	c.lastTimeInState = totals
+
+	var sum uint64
+	for _, t := range window {
+		sum += t
+	}
+	if sum > 0 {
+		for khz := range totals {
+			detail.TimeInState = append(detail.TimeInState, FrequencyResidency{
+				MHz:   float64(khz) / 1000,
+				Share: float64(window[khz]) / float64(sum) * 100,
+			})
+		}
+		sort.Slice(detail.TimeInState, func(i, j int) bool {
+			return detail.TimeInState[i].MHz < detail.TimeInState[j].MHz
+		})
+	}
+	return detail
+}
+
+// onlineCPUNames returns the CPUs seen by the last collection, or every
+// cpuN directory in sysfs before the first one.
+func (c *Collector) onlineCPUNames() []string {
+	if len(c.cpuNames) > 0 {
+		return c.cpuNames
+	}
+	matches, _ := filepath.Glob(filepath.Join(c.opts.SysfsRoot, "devices", "system", "cpu", "cpu[0-9]*"))
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = filepath.Base(m)
+	}
+	return names
+}
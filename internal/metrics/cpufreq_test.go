@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReadCoreFrequencies(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"devices/system/cpu/cpu0/cpufreq/scaling_cur_freq": "800000\n",
+		"devices/system/cpu/cpu1/cpufreq/scaling_cur_freq": "3400000\n",
+		// cpu2 has no cpufreq directory, cpu3 an unreadable value
+		"devices/system/cpu/cpu3/cpufreq/scaling_cur_freq": "n/a\n",
+	})
+
+	got := readCoreFrequencies(root, []string{"cpu0", "cpu1", "cpu2", "cpu3"})
+	want := []float64{800, 3400, 0, 0}
+	if !slices.Equal(got, want) {
+		t.Fatalf("readCoreFrequencies = %v, want %v", got, want)
+	}
+
+	m := &CPUMetrics{PerCoreFrequency: got}
+	lo, hi, avg := m.FrequencyStats()
+	if lo != 800 || hi != 3400 || avg != 2100 {
+		t.Errorf("FrequencyStats = %v, %v, %v; want 800, 3400, 2100 (cores without cpufreq skipped)", lo, hi, avg)
+	}
+}
@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// IdleState describes one cpuidle state (C-state) as reported by the
+// driver.
+type IdleState struct {
+	// Name is the short name ("POLL", "C1", "C6"), Desc the driver's
+	// description
+	Name string
+	Desc string
+	// Latency is the exit latency in microseconds
+	Latency uint64
+	// Disabled is set when the state is disabled on any CPU
+	Disabled bool
+}
+
+// IdleResidency is the share of an interval one CPU spent in each idle
+// state.
+type IdleResidency struct {
+	CPU int
+	// Active is the percentage of time outside every idle state (C0)
+	Active float64
+	// Residency is the percentage of time per state and Entries the
+	// number of entries per second, both aligned with IdleDetail.States
+	Residency []float64
+	Entries   []float64
+}
+
+// IdleDetail is the C-state residency of every online CPU between two
+// calls of CollectIdleDetail.
+type IdleDetail struct {
+	// Driver and Governor are the cpuidle driver ("intel_idle",
+	// "acpi_idle") and governor ("menu", "teo")
+	Driver   string
+	Governor string
+	States   []IdleState
+	Cores    []IdleResidency
+	// Average is the mean over Cores; its CPU is -1
+	Average IdleResidency
+	// Interval is the span the residencies cover. It is 0 on the first
+	// call, which only records the counters
+	Interval time.Duration
+}
+
+// idleCounters is one reading of a state's usage and time (µs) counters.
+type idleCounters struct {
+	usage uint64
+	time  uint64
+}
+
+// readIdleStates reads <cpu>/cpuidle/stateK for K = 0, 1, ... until a
+// state is missing.
+func readIdleStates(cpuDir string) ([]IdleState, []idleCounters) {
+	var states []IdleState
+	var counters []idleCounters
+	for k := 0; ; k++ {
+		dir := filepath.Join(cpuDir, "cpuidle", fmt.Sprintf("state%d", k))
+		usage, err := readUint(filepath.Join(dir, "usage"))
+		if err != nil {
+			break
+		}
+		t, err := readUint(filepath.Join(dir, "time"))
+		if err != nil {
+			break
+		}
+		state := IdleState{}
+		state.Name, _ = readString(filepath.Join(dir, "name"))
+		state.Desc, _ = readString(filepath.Join(dir, "desc"))
+		state.Latency, _ = readUint(filepath.Join(dir, "latency"))
+		if disabled, err := readUint(filepath.Join(dir, "disable")); err == nil && disabled != 0 {
+			state.Disabled = true
+		}
+		if state.Name == "" {
+			state.Name = fmt.Sprintf("state%d", k)
+		}
+		states = append(states, state)
+		counters = append(counters, idleCounters{usage: usage, time: t})
+	}
+	return states, counters
+}
+
+// CollectIdleDetail reads the cpuidle counters of every online CPU and
+// turns them into residency since the previous call. Like
+// CollectFrequencyDetail it reads many files, so it is only called while
+// the C-states screen is open.
+//
+// The kernel updates a state's time when the CPU leaves it, so a CPU that
+// sleeps across the whole interval can show less residency than it had;
+// shares are clamped so that Active never goes negative.
+func (c *Collector) CollectIdleDetail() *IdleDetail {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	detail := &IdleDetail{}
+	idleDir := filepath.Join(c.opts.SysfsRoot, "devices", "system", "cpu", "cpuidle")
+	detail.Driver, _ = readString(filepath.Join(idleDir, "current_driver"))
+	if governor, err := readString(filepath.Join(idleDir, "current_governor_ro")); err == nil {
+		detail.Governor = governor
+	} else {
+		detail.Governor, _ = readString(filepath.Join(idleDir, "current_governor"))
+	}
+
+	elapsed := now.Sub(c.lastIdleTime)
+	baseline := c.lastIdle == nil
+	current := make(map[string][]idleCounters)
+	for _, name := range c.onlineCPUNames() {
+		states, counters := readIdleStates(cpuSysfsDir(c.opts.SysfsRoot, name))
+		if len(states) == 0 {
+			continue
+		}
+		current[name] = counters
+		if len(states) > len(detail.States) {
+			// CPUs normally share one state table; keep the longest
+			disabled := make([]bool, len(detail.States))
+			for i, s := range detail.States {
+				disabled[i] = s.Disabled
+			}
+			detail.States = states
+			for i, d := range disabled {
+				detail.States[i].Disabled = detail.States[i].Disabled || d
+			}
+		} else {
+			for i, s := range states {
+				detail.States[i].Disabled = detail.States[i].Disabled || s.Disabled
+			}
+		}
+
+		id, _ := cpuNumber(name)
+		core := IdleResidency{CPU: id, Active: 100}
+		prev, seen := c.lastIdle[name]
+		if !baseline && seen && len(prev) == len(counters) && elapsed > 0 {
+			core.Residency = make([]float64, len(counters))
+			core.Entries = make([]float64, len(counters))
+			us := float64(elapsed.Microseconds())
+			idle := 0.0
+			for k, cur := range counters {
+				if cur.time > prev[k].time {
+					core.Residency[k] = min(float64(cur.time-prev[k].time)/us*100, 100-idle)
+					idle += core.Residency[k]
+				}
+				core.Entries[k] = counterRate(prev[k].usage, cur.usage, elapsed.Seconds())
+			}
+			core.Active = 100 - idle
+		}
+		detail.Cores = append(detail.Cores, core)
+	}
+	c.lastIdle = current
+	c.lastIdleTime = now
+	sort.Slice(detail.Cores, func(i, j int) bool { return detail.Cores[i].CPU < detail.Cores[j].CPU })
+
+	if baseline || len(detail.Cores) == 0 {
+		return detail
+	}
+	detail.Interval = elapsed
+
+	// Average over the CPUs that had a previous reading
+	avg := IdleResidency{
+		CPU:       -1,
+		Residency: make([]float64, len(detail.States)),
+		Entries:   make([]float64, len(detail.States)),
+	}
+	n := 0
+	for _, core := range detail.Cores {
+		if core.Residency == nil {
+			continue
+		}
+		n++
+		avg.Active += core.Active
+		for k := range core.Residency {
+			avg.Residency[k] += core.Residency[k]
+			avg.Entries[k] += core.Entries[k]
+		}
+	}
+	if n > 0 {
+		avg.Active /= float64(n)
+		for k := range avg.Residency {
+			avg.Residency[k] /= float64(n)
+			avg.Entries[k] /= float64(n)
+		}
+	}
+	detail.Average = avg
+	return detail
+}
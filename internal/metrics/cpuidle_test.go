@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// idleStateFiles returns the cpuidle files of one state of one CPU.
+func idleStateFiles(cpu, state, name string, latency, usage, timeUs string) map[string]string {
+	dir := "devices/system/cpu/" + cpu + "/cpuidle/" + state + "/"
+	return map[string]string{
+		dir + "name":    name + "\n",
+		dir + "desc":    name + " idle state\n",
+		dir + "latency": latency + "\n",
+		dir + "usage":   usage + "\n",
+		dir + "time":    timeUs + "\n",
+		dir + "disable": "0\n",
+	}
+}
+
+func TestCollectIdleDetail(t *testing.T) {
+	files := map[string]string{
+		"devices/system/cpu/cpuidle/current_driver":      "intel_idle\n",
+		"devices/system/cpu/cpuidle/current_governor_ro": "menu\n",
+		// cpu1 has no cpuidle directory and is left out
+		"devices/system/cpu/cpu1/online": "1\n",
+	}
+	for _, cpu := range []string{"cpu0", "cpu2"} {
+		for _, f := range []map[string]string{
+			idleStateFiles(cpu, "state0", "POLL", "0", "10", "100"),
+			idleStateFiles(cpu, "state1", "C1", "2", "1000", "1000000"),
+			idleStateFiles(cpu, "state2", "C6", "170", "500", "2000000"),
+		} {
+			for k, v := range f {
+				files[k] = v
+			}
+		}
+	}
+	root := writeTree(t, files)
+	c := NewCollector(Options{SysfsRoot: root, ProcfsRoot: t.TempDir(), CgroupRoot: t.TempDir()})
+
+	detail := c.CollectIdleDetail()
+	if detail.Interval != 0 || len(detail.Cores) != 2 {
+		t.Fatalf("baseline: interval %v, %d cores; want 0 and 2", detail.Interval, len(detail.Cores))
+	}
+	if detail.Driver != "intel_idle" || detail.Governor != "menu" || len(detail.States) != 3 {
+		t.Fatalf("baseline: driver %q, governor %q, %d states", detail.Driver, detail.Governor, len(detail.States))
+	}
+
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Over one second cpu0 spends 20% in C1 and 50% in C6. cpu2 reports
+	// 60% and 90%, since time is only updated on exit; C6 is clamped to
+	// the remaining 40%
+	write("devices/system/cpu/cpu0/cpuidle/state1/time", "1200000\n")
+	write("devices/system/cpu/cpu0/cpuidle/state1/usage", "1100\n")
+	write("devices/system/cpu/cpu0/cpuidle/state2/time", "2500000\n")
+	write("devices/system/cpu/cpu2/cpuidle/state1/time", "1600000\n")
+	write("devices/system/cpu/cpu2/cpuidle/state2/time", "2900000\n")
+	write("devices/system/cpu/cpu2/cpuidle/state2/disable", "1\n")
+	// cpu3 came online since the baseline
+	for k, v := range idleStateFiles("cpu3", "state0", "POLL", "0", "1", "1") {
+		write(k, v)
+	}
+	c.lastIdleTime = time.Now().Add(-time.Second)
+
+	detail = c.CollectIdleDetail()
+	if detail.Interval < time.Second {
+		t.Fatalf("interval %v, want about 1s", detail.Interval)
+	}
+	if !detail.States[2].Disabled || detail.States[1].Disabled {
+		t.Errorf("disabled = %v, %v; want only C6 disabled", detail.States[1].Disabled, detail.States[2].Disabled)
+	}
+	if len(detail.Cores) != 3 || detail.Cores[2].CPU != 3 || detail.Cores[2].Residency != nil {
+		t.Fatalf("cores = %+v; want cpu0, cpu2 and a new cpu3 without residency", detail.Cores)
+	}
+
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1 }
+	check := func(label string, r IdleResidency, active, c1, c6 float64) {
+		t.Helper()
+		if !near(r.Active, active) || !near(r.Residency[1], c1) || !near(r.Residency[2], c6) || r.Residency[0] != 0 {
+			t.Errorf("%s: active %.1f, residency %.1f; want %v, [0 %v %v]", label, r.Active, r.Residency, active, c1, c6)
+		}
+	}
+	check("cpu0", detail.Cores[0], 30, 20, 50)
+	check("cpu2 (clamped)", detail.Cores[1], 0, 60, 40)
+	if detail.Cores[1].Active < 0 {
+		t.Errorf("cpu2 active %v, must not go negative", detail.Cores[1].Active)
+	}
+	if e := detail.Cores[0].Entries[1]; !near(e, 100) {
+		t.Errorf("cpu0 C1 entries %.1f/s, want about 100", e)
+	}
+
+	// cpu3 has no previous reading and doesn't count toward the average
+	if detail.Average.CPU != -1 {
+		t.Errorf("average CPU = %d, want -1", detail.Average.CPU)
+	}
+	check("average", detail.Average, 15, 40, 45)
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"runtime"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// cpuTotals returns the total and busy time for a CPU times snapshot.
+// Guest time is already counted in user time on Linux, so it is excluded
+// from the total just like gopsutil does in cpu.Percent.
+func cpuTotals(t cpu.TimesStat) (total, busy float64) {
+	total = t.User + t.System + t.Idle + t.Nice + t.Iowait + t.Irq +
+		t.Softirq + t.Steal
+	if runtime.GOOS != "linux" {
+		total += t.Guest + t.GuestNice
+	}
+	busy = total - t.Idle - t.Iowait
+	return total, busy
+}
+
+// usageBetween returns the busy percentage between two snapshots of the
+// same CPU. A zero prev yields the average since boot.
+func usageBetween(prev, cur cpu.TimesStat) float64 {
+	prevTotal, prevBusy := cpuTotals(prev)
+	curTotal, curBusy := cpuTotals(cur)
+
+	deltaTotal := curTotal - prevTotal
+	if deltaTotal <= 0 {
+		return 0
+	}
+
+	usage := (curBusy - prevBusy) / deltaTotal * 100
+	if usage < 0 {
+		return 0
+	}
+	if usage > 100 {
+		return 100
+	}
+	return usage
+}
+
+// sumTimes adds up per-CPU snapshots into a single aggregate snapshot.
+func sumTimes(times []cpu.TimesStat) cpu.TimesStat {
+	sum := cpu.TimesStat{CPU: "cpu-total"}
+	for _, t := range times {
+		sum.User += t.User
+		sum.System += t.System
+		sum.Idle += t.Idle
+		sum.Nice += t.Nice
+		sum.Iowait += t.Iowait
+		sum.Irq += t.Irq
+		sum.Softirq += t.Softirq
+		sum.Steal += t.Steal
+		sum.Guest += t.Guest
+		sum.GuestNice += t.GuestNice
+	}
+	return sum
+}
+
+// CPUBreakdown is the share of time a CPU spent in each state, in percent.
+// User and Nice exclude guest time, so all fields add up to 100.
+type CPUBreakdown struct {
+	User      float64
+	Nice      float64
+	System    float64
+	Iowait    float64
+	Irq       float64
+	Softirq   float64
+	Steal     float64
+	Guest     float64
+	GuestNice float64
+	Idle      float64
+}
+
+// Busy returns the busy percentage, matching TotalUsage and PerCoreUsage.
+func (b CPUBreakdown) Busy() float64 {
+	return b.User + b.Nice + b.System + b.Irq + b.Softirq + b.Steal + b.Guest + b.GuestNice
+}
+
+// breakdownBetween returns the per-state percentages between two
+// snapshots of the same CPU.
+func breakdownBetween(prev, cur cpu.TimesStat) CPUBreakdown {
+	prevTotal, _ := cpuTotals(prev)
+	curTotal, _ := cpuTotals(cur)
+
+	deltaTotal := curTotal - prevTotal
+	if deltaTotal <= 0 {
+		return CPUBreakdown{}
+	}
+
+	pct := func(delta float64) float64 {
+		if delta <= 0 {
+			return 0
+		}
+		return delta / deltaTotal * 100
+	}
+
+	guest := cur.Guest - prev.Guest
+	guestNice := cur.GuestNice - prev.GuestNice
+	user := cur.User - prev.User
+	nice := cur.Nice - prev.Nice
+	if runtime.GOOS == "linux" {
+		user -= guest
+		nice -= guestNice
+	}
+
+	return CPUBreakdown{
+		User:      pct(user),
+		Nice:      pct(nice),
+		System:    pct(cur.System - prev.System),
+		Iowait:    pct(cur.Iowait - prev.Iowait),
+		Irq:       pct(cur.Irq - prev.Irq),
+		Softirq:   pct(cur.Softirq - prev.Softirq),
+		Steal:     pct(cur.Steal - prev.Steal),
+		Guest:     pct(guest),
+		GuestNice: pct(guestNice),
+		Idle:      pct(cur.Idle - prev.Idle),
+	}
+}
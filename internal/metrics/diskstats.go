@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// diskSectorSize is the unit of the sector counters in /proc/diskstats,
+// independent of the device's real sector size.
+const diskSectorSize = 512
+
+// DiskStats is the activity of one block device since the previous
+// collection.
+type DiskStats struct {
+	Name string
+	// Throughput in bytes per second and completed operations per second
+	ReadRate  float64
+	WriteRate float64
+	ReadIOPS  float64
+	WriteIOPS float64
+	// Average time per completed request in milliseconds, including queueing
+	ReadLatency  float64
+	WriteLatency float64
+	// Utilization is the percentage of time the device had I/O in flight
+	Utilization float64
+}
+
+// diskCounters are the cumulative /proc/diskstats fields we use.
+type diskCounters struct {
+	reads        uint64
+	sectorsRead  uint64
+	msReading    uint64
+	writes       uint64
+	sectorsWrite uint64
+	msWriting    uint64
+	msIO         uint64
+}
+
+func readDiskStats(path string) (map[string]diskCounters, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseDiskStats(f)
+}
+
+// parseDiskStats parses /proc/diskstats lines:
+//
+//	major minor name reads merged sectors ms writes merged sectors ms inflight ms_io weighted_ms ...
+func parseDiskStats(r io.Reader) (map[string]diskCounters, error) {
+	stats := make(map[string]diskCounters)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 14 {
+			continue
+		}
+		var v [11]uint64
+		ok := true
+		for i := range v {
+			n, err := strconv.ParseUint(fields[3+i], 10, 64)
+			if err != nil {
+				ok = false
+				break
+			}
+			v[i] = n
+		}
+		if !ok {
+			continue
+		}
+		stats[fields[2]] = diskCounters{
+			reads:        v[0],
+			sectorsRead:  v[2],
+			msReading:    v[3],
+			writes:       v[4],
+			sectorsWrite: v[6],
+			msWriting:    v[7],
+			msIO:         v[9],
+		}
+	}
+	return stats, scanner.Err()
+}
+
+// isWholeDisk reports whether name is a disk rather than a partition,
+// using <sysfs>/block which only lists whole devices. Loop and RAM disks
+// are skipped as noise.
+func isWholeDisk(sysfsRoot, name string) bool {
+	if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") {
+		return false
+	}
+	blockDir := filepath.Join(sysfsRoot, "block")
+	if _, err := os.Stat(blockDir); err != nil {
+		// No sysfs to ask (e.g. procfs fixtures): keep everything
+		return true
+	}
+	_, err := os.Stat(filepath.Join(blockDir, strings.ReplaceAll(name, "/", "!")))
+	return err == nil
+}
+
+// collectDisks computes per-device rates from /proc/diskstats deltas.
+// Devices that have never done any I/O are left out.
+func (c *Collector) collectDisks(metrics *CPUMetrics) {
+	now := time.Now()
+	stats, err := readDiskStats(filepath.Join(c.opts.ProcfsRoot, "diskstats"))
+	if err != nil {
+		return
+	}
+
+	elapsed := now.Sub(c.lastDiskTime).Seconds()
+	for name, cur := range stats {
+		if cur.reads == 0 && cur.writes == 0 {
+			continue
+		}
+		if !isWholeDisk(c.opts.SysfsRoot, name) {
+			continue
+		}
+		d := DiskStats{Name: name}
+		if prev, ok := c.lastDiskStats[name]; ok {
+			d.ReadRate = counterRate(prev.sectorsRead, cur.sectorsRead, elapsed) * diskSectorSize
+			d.WriteRate = counterRate(prev.sectorsWrite, cur.sectorsWrite, elapsed) * diskSectorSize
+			d.ReadIOPS = counterRate(prev.reads, cur.reads, elapsed)
+			d.WriteIOPS = counterRate(prev.writes, cur.writes, elapsed)
+			if cur.reads > prev.reads && cur.msReading >= prev.msReading {
+				d.ReadLatency = float64(cur.msReading-prev.msReading) / float64(cur.reads-prev.reads)
+			}
+			if cur.writes > prev.writes && cur.msWriting >= prev.msWriting {
+				d.WriteLatency = float64(cur.msWriting-prev.msWriting) / float64(cur.writes-prev.writes)
+			}
+			d.Utilization = counterRate(prev.msIO, cur.msIO, elapsed) / 1000 * 100
+			if d.Utilization > 100 {
+				d.Utilization = 100
+			}
+		}
+		metrics.Disks = append(metrics.Disks, d)
+	}
+	sort.Slice(metrics.Disks, func(i, j int) bool {
+		return metrics.Disks[i].Name < metrics.Disks[j].Name
+	})
+
+	c.lastDiskStats = stats
+	c.lastDiskTime = now
+}
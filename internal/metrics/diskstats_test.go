@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseDiskStats(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]diskCounters
+	}{
+		{
+			// Kernels before 4.18 have 14 fields
+			name: "14 fields",
+			input: "   8       0 sda 61420 2013 3401146 41876 98112 81250 5627696 192556 0 67212 234352\n" +
+				"   8       1 sda1 61206 2013 3392914 41796 95972 81250 5627696 190280 0 65424 232020\n",
+			want: map[string]diskCounters{
+				"sda":  {reads: 61420, sectorsRead: 3401146, msReading: 41876, writes: 98112, sectorsWrite: 5627696, msWriting: 192556, msIO: 67212},
+				"sda1": {reads: 61206, sectorsRead: 3392914, msReading: 41796, writes: 95972, sectorsWrite: 5627696, msWriting: 190280, msIO: 65424},
+			},
+		},
+		{
+			// 4.18 added four discard fields
+			name:  "18 fields",
+			input: "   8      16 sdb 1203 0 98230 812 45 12 1032 96 0 784 908 10 0 2048 4\n",
+			want: map[string]diskCounters{
+				"sdb": {reads: 1203, sectorsRead: 98230, msReading: 812, writes: 45, sectorsWrite: 1032, msWriting: 96, msIO: 784},
+			},
+		},
+		{
+			// 5.5 added two flush fields
+			name:  "20 fields",
+			input: " 259       0 nvme0n1 1195043 373467 76546298 305422 2474290 1612377 133985960 3004458 0 1418212 3385917 0 0 0 0 195419 76036\n",
+			want: map[string]diskCounters{
+				"nvme0n1": {reads: 1195043, sectorsRead: 76546298, msReading: 305422, writes: 2474290, sectorsWrite: 133985960, msWriting: 3004458, msIO: 1418212},
+			},
+		},
+		{
+			name: "short and malformed lines",
+			input: "   8       0 sda 1 2 3\n" +
+				"   8       0 sdc 1 2 x 4 5 6 7 8 9 10 11\n" +
+				"\n",
+			want: map[string]diskCounters{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDiskStats(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("got %d devices, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("%s = %+v, want %+v", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectDisksSkipsPartitions(t *testing.T) {
+	procfs := writeTree(t, map[string]string{
+		"diskstats": "   7       0 loop0 120 0 2200 10 0 0 0 0 0 20 10 0 0 0 0\n" +
+			"   8       0 sda 61420 2013 3401146 41876 98112 81250 5627696 192556 0 67212 234352 0 0 0 0\n" +
+			"   8       1 sda1 61206 2013 3392914 41796 95972 81250 5627696 190280 0 65424 232020 0 0 0 0\n" +
+			"   8      16 sdb 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0\n" +
+			" 259       0 nvme0n1 1195043 373467 76546298 305422 2474290 1612377 133985960 3004458 0 1418212 3385917 0 0 0 0 195419 76036\n" +
+			" 259       1 nvme0n1p1 520 0 41202 96 2 0 2 0 0 112 96 0 0 0 0 0 0\n",
+	})
+	// <sysfs>/block lists whole disks only
+	sysfs := writeTree(t, map[string]string{
+		"block/loop0/dev":   "7:0\n",
+		"block/sda/dev":     "8:0\n",
+		"block/sdb/dev":     "8:16\n",
+		"block/nvme0n1/dev": "259:0\n",
+	})
+	c := NewCollector(Options{ProcfsRoot: procfs, SysfsRoot: sysfs, CgroupRoot: t.TempDir()})
+	m := &CPUMetrics{}
+	c.collectDisks(m)
+
+	var names []string
+	for _, d := range m.Disks {
+		names = append(names, d.Name)
+	}
+	// Partitions, loop devices and sdb, which never did any I/O, are left out
+	if want := []string{"nvme0n1", "sda"}; !slices.Equal(names, want) {
+		t.Errorf("disks = %v, want %v", names, want)
+	}
+}
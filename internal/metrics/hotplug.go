@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// readOfflineCPUs returns the CPUs that are present but not online, from
+// <sysfs>/devices/system/cpu/{present,online}. Hot-added vCPUs that have
+// not been brought online yet show up here too.
+func readOfflineCPUs(sysfsRoot string) []int {
+	dir := filepath.Join(sysfsRoot, "devices", "system", "cpu")
+	readList := func(name string) ([]int, bool) {
+		s, err := readString(filepath.Join(dir, name))
+		if err != nil {
+			return nil, false
+		}
+		cpus, err := parseCPUList(s)
+		return cpus, err == nil
+	}
+
+	present, ok := readList("present")
+	if !ok {
+		return nil
+	}
+	online, ok := readList("online")
+	if !ok {
+		return nil
+	}
+
+	isOnline := make(map[int]bool, len(online))
+	for _, cpu := range online {
+		isOnline[cpu] = true
+	}
+	var offline []int
+	for _, cpu := range present {
+		if !isOnline[cpu] {
+			offline = append(offline, cpu)
+		}
+	}
+	sort.Ints(offline)
+	return offline
+}
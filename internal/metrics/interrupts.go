@@ -0,0 +1,221 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IRQRate is the per-CPU rate of one hardware interrupt line or softirq
+// type since the previous collection.
+type IRQRate struct {
+	// Name is the IRQ number or mnemonic ("24", "LOC", "NET_RX")
+	Name string
+	// Label describes the source: the device for numbered IRQs, the
+	// kernel's description for named ones
+	Label   string
+	Softirq bool
+	// PerCPU is aligned with CPUMetrics.InterruptCPUs
+	PerCPU []float64
+	Total  float64
+}
+
+// irqCounters is one parsed line of /proc/interrupts or /proc/softirqs.
+type irqCounters struct {
+	name   string
+	label  string
+	perCPU []uint64
+}
+
+// irqTable is a parsed /proc/interrupts or /proc/softirqs file. cpus holds
+// the CPU numbers of the columns; offline CPUs have no column.
+type irqTable struct {
+	cpus  []int
+	lines []irqCounters
+}
+
+func readIRQTable(path string) (irqTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return irqTable{}, err
+	}
+	defer f.Close()
+	return parseIRQTable(f)
+}
+
+// parseIRQTable parses the shared layout of /proc/interrupts and
+// /proc/softirqs: a "CPU0 CPU1 ..." header, then "NAME: count count ...
+// [description]" per line. Lines with fewer counts than CPUs (such as ERR
+// and MIS) are skipped.
+func parseIRQTable(r io.Reader) (irqTable, error) {
+	var t irqTable
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return t, err
+		}
+		return t, fmt.Errorf("empty interrupt table")
+	}
+	for _, field := range strings.Fields(scanner.Text()) {
+		n, err := strconv.Atoi(strings.TrimPrefix(field, "CPU"))
+		if err != nil {
+			return t, fmt.Errorf("unexpected interrupt table header %q", field)
+		}
+		t.cpus = append(t.cpus, n)
+	}
+
+	for scanner.Scan() {
+		name, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) < len(t.cpus) {
+			continue
+		}
+		line := irqCounters{
+			name:   strings.TrimSpace(name),
+			perCPU: make([]uint64, len(t.cpus)),
+		}
+		valid := true
+		for i := range t.cpus {
+			v, err := strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				valid = false
+				break
+			}
+			line.perCPU[i] = v
+		}
+		if !valid {
+			continue
+		}
+		line.label = irqLabel(line.name, fields[len(t.cpus):])
+		t.lines = append(t.lines, line)
+	}
+	return t, scanner.Err()
+}
+
+// irqLabel picks a short description. Numbered IRQs end in the device
+// name(s) after the chip and trigger type; named ones have a sentence.
+func irqLabel(name string, desc []string) string {
+	if len(desc) == 0 {
+		return ""
+	}
+	if _, err := strconv.Atoi(name); err == nil {
+		// Shared lines list every device: "2-edge nvme0q1, nvme0q2"
+		for i, field := range desc {
+			if isIRQTrigger(field) && i+1 < len(desc) {
+				return strings.Join(desc[i+1:], " ")
+			}
+		}
+		return desc[len(desc)-1]
+	}
+	return strings.Join(desc, " ")
+}
+
+// isIRQTrigger reports whether field is the trigger type of a numbered
+// IRQ, such as "2-edge", "PCI-MSI-edge" or "9-fasteoi".
+func isIRQTrigger(field string) bool {
+	return strings.Contains(field, "-edge") || strings.Contains(field, "-level") ||
+		strings.Contains(field, "-fasteoi")
+}
+
+// irqRates turns two readings of a table into rates, keyed by kind and
+// name so hardware and softirq names can't collide.
+func irqRates(prev map[string][]uint64, cur irqTable, softirq bool, elapsed float64) []IRQRate {
+	rates := make([]IRQRate, 0, len(cur.lines))
+	for _, line := range cur.lines {
+		r := IRQRate{
+			Name:    line.name,
+			Label:   line.label,
+			Softirq: softirq,
+			PerCPU:  make([]float64, len(line.perCPU)),
+		}
+		if before, ok := prev[irqKey(softirq, line.name)]; ok && len(before) == len(line.perCPU) {
+			for i := range line.perCPU {
+				r.PerCPU[i] = counterRate(before[i], line.perCPU[i], elapsed)
+				r.Total += r.PerCPU[i]
+			}
+		}
+		rates = append(rates, r)
+	}
+	return rates
+}
+
+func irqKey(softirq bool, name string) string {
+	if softirq {
+		return "softirq:" + name
+	}
+	return "irq:" + name
+}
+
+// collectInterrupts computes per-CPU rates for every hardware IRQ and
+// softirq type, busiest first.
+func (c *Collector) collectInterrupts(metrics *CPUMetrics) {
+	now := time.Now()
+	hard, errHard := readIRQTable(filepath.Join(c.opts.ProcfsRoot, "interrupts"))
+	soft, errSoft := readIRQTable(filepath.Join(c.opts.ProcfsRoot, "softirqs"))
+	if errHard != nil && errSoft != nil {
+		return
+	}
+
+	elapsed := now.Sub(c.lastIRQTime).Seconds()
+	if errHard == nil {
+		metrics.InterruptCPUs = hard.cpus
+		metrics.Interrupts = append(metrics.Interrupts, irqRates(c.lastIRQ, hard, false, elapsed)...)
+	}
+	if errSoft == nil {
+		softRates := irqRates(c.lastIRQ, soft, true, elapsed)
+		if errHard != nil {
+			metrics.InterruptCPUs = soft.cpus
+		} else if !slices.Equal(hard.cpus, soft.cpus) {
+			// softirqs lists every possible CPU, interrupts only the
+			// online ones
+			alignIRQColumns(softRates, soft.cpus, hard.cpus)
+		}
+		metrics.Interrupts = append(metrics.Interrupts, softRates...)
+	}
+	sort.SliceStable(metrics.Interrupts, func(i, j int) bool {
+		return metrics.Interrupts[i].Total > metrics.Interrupts[j].Total
+	})
+
+	last := make(map[string][]uint64, len(hard.lines)+len(soft.lines))
+	for _, line := range hard.lines {
+		last[irqKey(false, line.name)] = line.perCPU
+	}
+	for _, line := range soft.lines {
+		last[irqKey(true, line.name)] = line.perCPU
+	}
+	c.lastIRQ = last
+	c.lastIRQTime = now
+}
+
+// alignIRQColumns rearranges PerCPU from the from CPU columns to the to
+// columns, dropping CPUs missing from to.
+func alignIRQColumns(rates []IRQRate, from, to []int) {
+	index := make(map[int]int, len(from))
+	for i, cpu := range from {
+		index[cpu] = i
+	}
+	for r := range rates {
+		aligned := make([]float64, len(to))
+		total := 0.0
+		for i, cpu := range to {
+			if j, ok := index[cpu]; ok && j < len(rates[r].PerCPU) {
+				aligned[i] = rates[r].PerCPU[j]
+				total += aligned[i]
+			}
+		}
+		rates[r].PerCPU = aligned
+		rates[r].Total = total
+	}
+}
@@ -0,0 +1,182 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// CPU2 is offline: /proc/interrupts has no column for it
+const interruptsFixture = `           CPU0       CPU1       CPU3       
+  0:         36          0          0   IO-APIC   2-edge      timer
+  8:          0          0          1   IO-APIC   8-edge      rtc0
+  9:          0          4          0   IO-APIC   9-fasteoi   acpi
+ 24:     120000       3400          0   PCI-MSI 327680-edge      xhci_hcd
+ 25:          7     880000         12   PCI-MSI 1048576-edge      nvme0q1, nvme0q2
+NMI:         11         12         13   Non-maskable interrupts
+LOC:    5000000    4000000    3000000   Local timer interrupts
+RES:      10000      20000      30000   Rescheduling interrupts
+ERR:          0
+MIS:          0
+`
+
+// /proc/softirqs lists every possible CPU, online or not
+const softirqsFixture = `                    CPU0       CPU1       CPU2       CPU3
+          HI:          1          0          0          2
+       TIMER:     100000      90000          0      80000
+      NET_RX:       5000        400          0         30
+`
+
+func TestParseIRQTable(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		cpus   []int
+		names  []string
+		labels map[string]string
+	}{
+		{
+			name:  "interrupts",
+			input: interruptsFixture,
+			cpus:  []int{0, 1, 3},
+			// ERR and MIS have a single count and are skipped
+			names: []string{"0", "8", "9", "24", "25", "NMI", "LOC", "RES"},
+			labels: map[string]string{
+				"24":  "xhci_hcd",
+				"0":   "timer",
+				"9":   "acpi",
+				"25":  "nvme0q1, nvme0q2",
+				"LOC": "Local timer interrupts",
+			},
+		},
+		{
+			name:   "softirqs",
+			input:  softirqsFixture,
+			cpus:   []int{0, 1, 2, 3},
+			names:  []string{"HI", "TIMER", "NET_RX"},
+			labels: map[string]string{"TIMER": ""},
+		},
+		{
+			name:  "malformed counts",
+			input: "CPU0 CPU1\n  1: 10 x\n  2: 10 20 IO-APIC 2-edge foo\nbogus line\n",
+			cpus:  []int{0, 1},
+			names: []string{"2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := parseIRQTable(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(table.cpus, tt.cpus) {
+				t.Errorf("cpus = %v, want %v", table.cpus, tt.cpus)
+			}
+			var names []string
+			labels := make(map[string]string)
+			for _, line := range table.lines {
+				names = append(names, line.name)
+				labels[line.name] = line.label
+				if len(line.perCPU) != len(tt.cpus) {
+					t.Errorf("%s has %d counts, want %d", line.name, len(line.perCPU), len(tt.cpus))
+				}
+			}
+			if !slices.Equal(names, tt.names) {
+				t.Errorf("names = %v, want %v", names, tt.names)
+			}
+			for name, want := range tt.labels {
+				if labels[name] != want {
+					t.Errorf("label of %s = %q, want %q", name, labels[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseIRQTableErrors(t *testing.T) {
+	for _, input := range []string{"", "IRQ CPU0\n"} {
+		if _, err := parseIRQTable(strings.NewReader(input)); err == nil {
+			t.Errorf("parseIRQTable(%q) succeeded, want an error", input)
+		}
+	}
+}
+
+func TestIRQRates(t *testing.T) {
+	cur := irqTable{
+		cpus: []int{0, 1},
+		lines: []irqCounters{
+			{name: "LOC", perCPU: []uint64{3000, 500}},
+			{name: "24", perCPU: []uint64{100, 100}},
+			{name: "NEW", perCPU: []uint64{50, 50}},
+			{name: "RES", perCPU: []uint64{10, 10}},
+		},
+	}
+	prev := map[string][]uint64{
+		irqKey(false, "LOC"): {1000, 500},
+		irqKey(false, "24"):  {200, 0}, // CPU0 counter reset
+		irqKey(true, "RES"):  {0, 0},   // a softirq of the same name
+	}
+
+	tests := []struct {
+		name   string
+		perCPU []float64
+		total  float64
+	}{
+		{"LOC", []float64{1000, 0}, 1000},
+		{"24", []float64{0, 50}, 50},
+		{"NEW", []float64{0, 0}, 0},
+		{"RES", []float64{0, 0}, 0},
+	}
+	rates := irqRates(prev, cur, false, 2)
+	if len(rates) != len(tests) {
+		t.Fatalf("got %d rates, want %d", len(rates), len(tests))
+	}
+	for i, tt := range tests {
+		r := rates[i]
+		if r.Name != tt.name || r.Softirq || !slices.Equal(r.PerCPU, tt.perCPU) || r.Total != tt.total {
+			t.Errorf("rate %d = %+v, want %s %v total %v", i, r, tt.name, tt.perCPU, tt.total)
+		}
+	}
+}
+
+func TestAlignIRQColumns(t *testing.T) {
+	rates := []IRQRate{{Name: "TIMER", Softirq: true, PerCPU: []float64{1, 2, 99, 3}, Total: 105}}
+	alignIRQColumns(rates, []int{0, 1, 2, 3}, []int{0, 1, 3})
+	if !slices.Equal(rates[0].PerCPU, []float64{1, 2, 3}) || rates[0].Total != 6 {
+		t.Errorf("aligned = %v total %v, want [1 2 3] total 6", rates[0].PerCPU, rates[0].Total)
+	}
+}
+
+func TestCollectInterruptsAlignsSoftirqs(t *testing.T) {
+	procfs := writeTree(t, map[string]string{
+		"interrupts": interruptsFixture,
+		"softirqs":   softirqsFixture,
+	})
+	c := NewCollector(Options{ProcfsRoot: procfs, SysfsRoot: t.TempDir(), CgroupRoot: t.TempDir()})
+	c.collectInterrupts(&CPUMetrics{})
+
+	// One second later TIMER rose by 10 on CPU0 and by 5 on CPU3
+	soft := strings.Replace(softirqsFixture, "100000      90000          0      80000",
+		"100010      90000          0      80005", 1)
+	if err := os.WriteFile(filepath.Join(procfs, "softirqs"), []byte(soft), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c.lastIRQTime = time.Now().Add(-time.Second)
+	m := &CPUMetrics{}
+	c.collectInterrupts(m)
+
+	if !slices.Equal(m.InterruptCPUs, []int{0, 1, 3}) {
+		t.Fatalf("InterruptCPUs = %v, want [0 1 3]", m.InterruptCPUs)
+	}
+	for _, r := range m.Interrupts {
+		if len(r.PerCPU) != 3 {
+			t.Errorf("%s has %d columns, want 3", r.Name, len(r.PerCPU))
+		}
+	}
+	if top := m.Interrupts[0]; top.Name != "TIMER" || !top.Softirq || top.PerCPU[0] < 9 || top.PerCPU[2] < 4.5 {
+		t.Errorf("busiest = %+v, want TIMER with ~10/s on CPU0 and ~5/s on CPU3", top)
+	}
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NetStats is the traffic of one network interface since the previous
+// collection, in bytes, packets, errors and drops per second.
+type NetStats struct {
+	Name      string
+	RxRate    float64
+	TxRate    float64
+	RxPackets float64
+	TxPackets float64
+	RxErrors  float64
+	TxErrors  float64
+	RxDrops   float64
+	TxDrops   float64
+	// Kinds of interface that are usually noise on container hosts
+	Loopback bool
+	Veth     bool
+	Bridge   bool
+}
+
+// netCounters are the cumulative /proc/net/dev fields we use.
+type netCounters struct {
+	rxBytes, rxPackets, rxErrs, rxDrop uint64
+	txBytes, txPackets, txErrs, txDrop uint64
+}
+
+func readNetDev(path string) (map[string]netCounters, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseNetDev(f)
+}
+
+// parseNetDev parses /proc/net/dev. After two header lines each line is
+// "iface: rx bytes packets errs drop fifo frame compressed multicast"
+// followed by "tx bytes packets errs drop fifo colls carrier compressed".
+func parseNetDev(r io.Reader) (map[string]netCounters, error) {
+	stats := make(map[string]netCounters)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		name, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) < 16 {
+			continue
+		}
+		var v [16]uint64
+		for i := range v {
+			v[i], _ = strconv.ParseUint(fields[i], 10, 64)
+		}
+		stats[strings.TrimSpace(name)] = netCounters{
+			rxBytes: v[0], rxPackets: v[1], rxErrs: v[2], rxDrop: v[3],
+			txBytes: v[8], txPackets: v[9], txErrs: v[10], txDrop: v[11],
+		}
+	}
+	return stats, scanner.Err()
+}
+
+// classifyInterface flags loopback, veth and bridge interfaces using
+// <sysfs>/class/net, falling back to the usual names without sysfs.
+func classifyInterface(sysfsRoot string, s *NetStats) {
+	dir := filepath.Join(sysfsRoot, "class", "net", s.Name)
+	// ARPHRD_LOOPBACK
+	if t, err := readString(filepath.Join(dir, "type")); err == nil {
+		s.Loopback = t == "772"
+	} else {
+		s.Loopback = s.Name == "lo"
+	}
+	s.Veth = strings.HasPrefix(s.Name, "veth")
+	if _, err := os.Stat(filepath.Join(dir, "bridge")); err == nil {
+		s.Bridge = true
+	}
+}
+
+// collectNetwork computes per-interface rates from /proc/net/dev deltas.
+func (c *Collector) collectNetwork(metrics *CPUMetrics) {
+	now := time.Now()
+	stats, err := readNetDev(filepath.Join(c.opts.ProcfsRoot, "net", "dev"))
+	if err != nil {
+		return
+	}
+
+	elapsed := now.Sub(c.lastNetTime).Seconds()
+	for name, cur := range stats {
+		n := NetStats{Name: name}
+		if prev, ok := c.lastNetStats[name]; ok {
+			n.RxRate = counterRate(prev.rxBytes, cur.rxBytes, elapsed)
+			n.TxRate = counterRate(prev.txBytes, cur.txBytes, elapsed)
+			n.RxPackets = counterRate(prev.rxPackets, cur.rxPackets, elapsed)
+			n.TxPackets = counterRate(prev.txPackets, cur.txPackets, elapsed)
+			n.RxErrors = counterRate(prev.rxErrs, cur.rxErrs, elapsed)
+			n.TxErrors = counterRate(prev.txErrs, cur.txErrs, elapsed)
+			n.RxDrops = counterRate(prev.rxDrop, cur.rxDrop, elapsed)
+			n.TxDrops = counterRate(prev.txDrop, cur.txDrop, elapsed)
+		}
+		classifyInterface(c.opts.SysfsRoot, &n)
+		metrics.Network = append(metrics.Network, n)
+	}
+	sort.Slice(metrics.Network, func(i, j int) bool {
+		return metrics.Network[i].Name < metrics.Network[j].Name
+	})
+
+	c.lastNetStats = stats
+	c.lastNetTime = now
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+const netDevFixture = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 8765432   54321    0    0    0     0          0         0  8765432   54321    0    0    0     0       0          0
+  eth0: 1523456789 2345678   12   34    0     0          0      1024 987654321 1234567    5    6    0     0       0          0
+docker0:   40960     512    0    0    0     0          0         0   819200     640    0    1    0     0       0          0
+vethab12cd3: 819200     640    0    0    0     0          0         0    40960     512    0    0    0     0       0          0
+ short: 1 2 3
+`
+
+func TestParseNetDev(t *testing.T) {
+	got, err := parseNetDev(strings.NewReader(netDevFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]netCounters{
+		"lo": {rxBytes: 8765432, rxPackets: 54321, txBytes: 8765432, txPackets: 54321},
+		"eth0": {
+			rxBytes: 1523456789, rxPackets: 2345678, rxErrs: 12, rxDrop: 34,
+			txBytes: 987654321, txPackets: 1234567, txErrs: 5, txDrop: 6,
+		},
+		"docker0":     {rxBytes: 40960, rxPackets: 512, txBytes: 819200, txPackets: 640, txDrop: 1},
+		"vethab12cd3": {rxBytes: 819200, rxPackets: 640, txBytes: 40960, txPackets: 512},
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d interfaces, want %d (header and short lines skipped): %v", len(got), len(want), got)
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("%s = %+v, want %+v", name, got[name], w)
+		}
+	}
+}
+
+func TestClassifyInterface(t *testing.T) {
+	sysfs := writeTree(t, map[string]string{
+		"class/net/lo/type":                  "772\n",
+		"class/net/eth0/type":                "1\n",
+		"class/net/docker0/type":             "1\n",
+		"class/net/docker0/bridge/stp_state": "0\n",
+		"class/net/vethab12cd3/type":         "1\n",
+	})
+	tests := []struct {
+		name                   string
+		sysfs                  string
+		loopback, veth, bridge bool
+	}{
+		{"lo", sysfs, true, false, false},
+		{"eth0", sysfs, false, false, false},
+		{"docker0", sysfs, false, false, true},
+		{"vethab12cd3", sysfs, false, true, false},
+		// Without sysfs only the names are known
+		{"lo", t.TempDir(), true, false, false},
+		{"docker0", t.TempDir(), false, false, false},
+	}
+	for _, tt := range tests {
+		s := NetStats{Name: tt.name}
+		classifyInterface(tt.sysfs, &s)
+		if s.Loopback != tt.loopback || s.Veth != tt.veth || s.Bridge != tt.bridge {
+			t.Errorf("%s: loopback %v veth %v bridge %v; want %v %v %v",
+				tt.name, s.Loopback, s.Veth, s.Bridge, tt.loopback, tt.veth, tt.bridge)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PressureStats is one line of a PSI file: the share of wall time in which
+// tasks were stalled, averaged over 10s, 60s and 300s, plus the total stall
+// time in microseconds.
+type PressureStats struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  uint64
+}
+
+// Pressure holds the "some" (at least one task stalled) and "full" (all
+// non-idle tasks stalled) figures for one resource. System-wide CPU full
+// pressure is always zero, and older kernels omit it.
+type Pressure struct {
+	Some PressureStats
+	Full PressureStats
+}
+
+// PressureMetrics is the Pressure Stall Information for CPU, memory and IO.
+// Available is false when the kernel lacks PSI (CONFIG_PSI or psi=0).
+type PressureMetrics struct {
+	Available bool
+	CPU       Pressure
+	Memory    Pressure
+	IO        Pressure
+}
+
+// readPressureMetrics reads the PSI files under procfsRoot/pressure.
+func readPressureMetrics(procfsRoot string) PressureMetrics {
+	var pm PressureMetrics
+	dir := filepath.Join(procfsRoot, "pressure")
+	for _, res := range []struct {
+		name string
+		dst  *Pressure
+	}{
+		{"cpu", &pm.CPU},
+		{"memory", &pm.Memory},
+		{"io", &pm.IO},
+	} {
+		p, err := readPressure(filepath.Join(dir, res.name))
+		if err != nil {
+			continue
+		}
+		*res.dst = p
+		pm.Available = true
+	}
+	return pm
+}
+
+func readPressure(path string) (Pressure, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Pressure{}, err
+	}
+	defer f.Close()
+	return parsePressure(f)
+}
+
+// parsePressure parses the PSI format:
+//
+//	some avg10=0.12 avg60=0.05 avg300=0.01 total=123456
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePressure(r io.Reader) (Pressure, error) {
+	var p Pressure
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var stats *PressureStats
+		switch fields[0] {
+		case "some":
+			stats = &p.Some
+		case "full":
+			stats = &p.Full
+		default:
+			continue
+		}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "avg10":
+				stats.Avg10, _ = strconv.ParseFloat(value, 64)
+			case "avg60":
+				stats.Avg60, _ = strconv.ParseFloat(value, 64)
+			case "avg300":
+				stats.Avg300, _ = strconv.ParseFloat(value, 64)
+			case "total":
+				stats.Total, _ = strconv.ParseUint(value, 10, 64)
+			}
+		}
+	}
+	return p, scanner.Err()
+}
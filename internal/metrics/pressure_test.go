@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePressure(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Pressure
+	}{
+		{
+			name: "cpu",
+			input: "some avg10=1.53 avg60=0.87 avg300=0.35 total=176253464\n" +
+				"full avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+			want: Pressure{Some: PressureStats{Avg10: 1.53, Avg60: 0.87, Avg300: 0.35, Total: 176253464}},
+		},
+		{
+			// Kernels before 5.13 have no full line for cpu
+			name:  "cpu without full",
+			input: "some avg10=0.12 avg60=0.05 avg300=0.01 total=123456\n",
+			want:  Pressure{Some: PressureStats{Avg10: 0.12, Avg60: 0.05, Avg300: 0.01, Total: 123456}},
+		},
+		{
+			name: "memory",
+			input: "some avg10=12.40 avg60=8.10 avg300=2.05 total=9876543\n" +
+				"full avg10=10.00 avg60=6.25 avg300=1.50 total=7654321\n",
+			want: Pressure{
+				Some: PressureStats{Avg10: 12.40, Avg60: 8.10, Avg300: 2.05, Total: 9876543},
+				Full: PressureStats{Avg10: 10.00, Avg60: 6.25, Avg300: 1.50, Total: 7654321},
+			},
+		},
+		{
+			name: "io",
+			input: "some avg10=0.00 avg60=0.14 avg300=0.29 total=4092338\n" +
+				"full avg10=0.00 avg60=0.11 avg300=0.22 total=3313442\n",
+			want: Pressure{
+				Some: PressureStats{Avg60: 0.14, Avg300: 0.29, Total: 4092338},
+				Full: PressureStats{Avg60: 0.11, Avg300: 0.22, Total: 3313442},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePressure(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("parsePressure = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadPressureMetrics(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"pressure/cpu":    "some avg10=2.00 avg60=1.00 avg300=0.50 total=1000\n",
+		"pressure/memory": "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+	})
+	pm := readPressureMetrics(root)
+	if !pm.Available || pm.CPU.Some.Avg10 != 2 || pm.IO != (Pressure{}) {
+		t.Errorf("readPressureMetrics = %+v; want available, cpu some avg10 2 and no io", pm)
+	}
+
+	if pm := readPressureMetrics(t.TempDir()); pm.Available {
+		t.Error("readPressureMetrics without pressure files reports Available")
+	}
+}
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ThreadInfo is one thread of a process with its CPU usage since the
+// previous detail collection.
+type ThreadInfo struct {
+	TID        int32
+	Name       string
+	CPUPercent float64
+	CPUTime    float64
+}
+
+// ProcessDetail is the drill-down information for a single process.
+// Fields the kernel refuses to reveal (e.g. another user's environment)
+// are left empty.
+type ProcessDetail struct {
+	PID      int32
+	Name     string
+	Cmdline  []string
+	Cwd      string
+	EnvVars  int
+	EnvBytes int
+	NumFDs   int32
+	Cgroup   string
+	Affinity string
+	Threads  []ThreadInfo
+}
+
+// threadSample remembers thread CPU times for one process between calls.
+type threadSample struct {
+	pid   int32
+	times map[int32]float64
+	at    time.Time
+}
+
+// CollectProcessDetail gathers drill-down information for pid. Thread CPU
+// usage is computed against the previous call for the same pid.
+func (c *Collector) CollectProcessDetail(pid int32) (*ProcessDetail, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, err := process.NewProcessWithContext(c.ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &ProcessDetail{PID: pid}
+	detail.Name, _ = p.NameWithContext(c.ctx)
+	detail.Cmdline, _ = p.CmdlineSliceWithContext(c.ctx)
+	detail.Cwd, _ = p.CwdWithContext(c.ctx)
+	detail.NumFDs, _ = p.NumFDsWithContext(c.ctx)
+	if env, err := p.EnvironWithContext(c.ctx); err == nil {
+		for _, kv := range env {
+			if kv == "" {
+				continue
+			}
+			detail.EnvVars++
+			detail.EnvBytes += len(kv) + 1
+		}
+	}
+
+	procDir := filepath.Join(c.opts.ProcfsRoot, strconv.Itoa(int(pid)))
+	detail.Cgroup = readCgroupPath(filepath.Join(procDir, "cgroup"))
+	detail.Affinity = readStatusField(filepath.Join(procDir, "status"), "Cpus_allowed_list")
+
+	now := time.Now()
+	threads, err := p.ThreadsWithContext(c.ctx)
+	if err == nil {
+		prev := c.threadSample
+		sample := threadSample{pid: pid, times: make(map[int32]float64, len(threads)), at: now}
+		for tid, t := range threads {
+			cpuTime := t.User + t.System
+			info := ThreadInfo{TID: tid, CPUTime: cpuTime}
+			if prevTime, ok := prev.times[tid]; ok && prev.pid == pid {
+				if elapsed := now.Sub(prev.at).Seconds(); elapsed > 0 {
+					info.CPUPercent = (cpuTime - prevTime) / elapsed * 100
+				}
+			}
+			if info.CPUPercent < 0 {
+				info.CPUPercent = 0
+			}
+			info.Name, _ = readString(filepath.Join(procDir, "task", strconv.Itoa(int(tid)), "comm"))
+			sample.times[tid] = cpuTime
+			detail.Threads = append(detail.Threads, info)
+		}
+		c.threadSample = sample
+
+		sort.Slice(detail.Threads, func(i, j int) bool {
+			if detail.Threads[i].CPUPercent != detail.Threads[j].CPUPercent {
+				return detail.Threads[i].CPUPercent > detail.Threads[j].CPUPercent
+			}
+			return detail.Threads[i].TID < detail.Threads[j].TID
+		})
+	}
+
+	return detail, nil
+}
+
+// readCgroupPath returns the cgroup v2 path from a /proc/<pid>/cgroup file,
+// or the cpu controller's path on cgroup v1 hosts.
+func readCgroupPath(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	var v1 string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			return parts[2]
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			if controller == "cpu" {
+				v1 = parts[2]
+			}
+		}
+	}
+	return v1
+}
+
+// readStatusField returns the value of a "Key:\tvalue" line from a
+// /proc/<pid>/status file.
+func readStatusField(path, key string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && name == key {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
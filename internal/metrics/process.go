@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"context"
+	"os/user"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ProcessInfo is a snapshot of one process for the process table.
+type ProcessInfo struct {
+	PID     int32
+	PPID    int32
+	User    string
+	Name    string
+	Cmdline string
+	// CPUPercent is the share of one CPU used since the previous
+	// collection, so multi-threaded processes can exceed 100
+	CPUPercent float64
+	// CPUTime is the cumulative user+system time in seconds
+	CPUTime    float64
+	MemPercent float64
+	RSS        uint64
+	State      string
+	Threads    int32
+	Nice       int32
+	CreateTime int64
+}
+
+// processSample remembers a process's CPU time for delta computation.
+// The create time guards against PID reuse between collections.
+type processSample struct {
+	createTime int64
+	cpuTime    float64
+	at         time.Time
+}
+
+// processTracker turns cumulative per-process CPU times into rates.
+type processTracker struct {
+	samples   map[int32]processSample
+	userNames map[int32]string
+}
+
+func newProcessTracker() *processTracker {
+	return &processTracker{
+		samples:   make(map[int32]processSample),
+		userNames: make(map[int32]string),
+	}
+}
+
+// collect lists all processes and computes per-process CPU usage from the
+// CPU time consumed since the previous call. Processes that exit while
+// being read are skipped.
+func (t *processTracker) collect(ctx context.Context) ([]ProcessInfo, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	samples := make(map[int32]processSample, len(procs))
+	infos := make([]ProcessInfo, 0, len(procs))
+
+	for _, p := range procs {
+		times, err := p.TimesWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		createTime, _ := p.CreateTimeWithContext(ctx)
+		cpuTime := times.User + times.System
+
+		info := ProcessInfo{
+			PID:        p.Pid,
+			CPUTime:    cpuTime,
+			CreateTime: createTime,
+		}
+
+		if prev, ok := t.samples[p.Pid]; ok && prev.createTime == createTime {
+			if elapsed := now.Sub(prev.at).Seconds(); elapsed > 0 {
+				info.CPUPercent = (cpuTime - prev.cpuTime) / elapsed * 100
+			}
+		} else if createTime > 0 {
+			// First sighting: average since the process started
+			if elapsed := now.Sub(time.UnixMilli(createTime)).Seconds(); elapsed > 0 {
+				info.CPUPercent = cpuTime / elapsed * 100
+			}
+		}
+		if info.CPUPercent < 0 {
+			info.CPUPercent = 0
+		}
+		samples[p.Pid] = processSample{createTime: createTime, cpuTime: cpuTime, at: now}
+
+		info.PPID, _ = p.PpidWithContext(ctx)
+		info.Name, _ = p.NameWithContext(ctx)
+		info.Cmdline, _ = p.CmdlineWithContext(ctx)
+		if info.Cmdline == "" {
+			// Kernel threads have no command line
+			info.Cmdline = "[" + info.Name + "]"
+		}
+		if mem, err := p.MemoryInfoWithContext(ctx); err == nil {
+			info.RSS = mem.RSS
+		}
+		if status, err := p.StatusWithContext(ctx); err == nil && len(status) > 0 {
+			info.State = stateLetter(status[0])
+		}
+		info.Threads, _ = p.NumThreadsWithContext(ctx)
+		if nice, err := p.NiceWithContext(ctx); err == nil {
+			// gopsutil returns the raw getpriority(2) value, which the
+			// Linux syscall reports as 20-nice
+			if runtime.GOOS == "linux" {
+				nice = 20 - nice
+			}
+			info.Nice = nice
+		}
+		if uids, err := p.UidsWithContext(ctx); err == nil && len(uids) > 0 {
+			info.User = t.userName(uids[0])
+		}
+
+		infos = append(infos, info)
+	}
+
+	// Dropping the old map forgets processes that have exited
+	t.samples = samples
+	return infos, nil
+}
+
+// userName resolves and caches a uid's login name, falling back to the
+// numeric uid when it has no passwd entry.
+func (t *processTracker) userName(uid int32) string {
+	if name, ok := t.userNames[uid]; ok {
+		return name
+	}
+	name := strconv.Itoa(int(uid))
+	if u, err := user.LookupId(name); err == nil {
+		name = u.Username
+	}
+	t.userNames[uid] = name
+	return name
+}
+
+// stateLetter converts gopsutil's status names to ps-style state letters.
+func stateLetter(status string) string {
+	switch status {
+	case process.Running:
+		return "R"
+	case process.Sleep:
+		return "S"
+	case process.Idle:
+		return "I"
+	case process.Stop:
+		return "T"
+	case process.Zombie:
+		return "Z"
+	case process.Blocked:
+		return "D"
+	case process.Wait:
+		return "W"
+	case process.Lock:
+		return "L"
+	default:
+		return "?"
+	}
+}
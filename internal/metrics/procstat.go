@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// procStatCounters are the scheduler counters from /proc/stat. ctxt, intr
+// and processes are cumulative; procs_running and procs_blocked are gauges.
+type procStatCounters struct {
+	ctxt         uint64
+	intr         uint64
+	processes    uint64
+	procsRunning uint64
+	procsBlocked uint64
+}
+
+func readProcStat(path string) (procStatCounters, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return procStatCounters{}, err
+	}
+	defer f.Close()
+	return parseProcStat(f)
+}
+
+// parseProcStat picks the scheduler lines out of /proc/stat. The intr line
+// lists per-IRQ counts after the total; only the total is used.
+func parseProcStat(r io.Reader) (procStatCounters, error) {
+	var s procStatCounters
+	scanner := bufio.NewScanner(r)
+	// The intr line has one field per IRQ and can exceed the default buffer
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		var dst *uint64
+		switch fields[0] {
+		case "ctxt":
+			dst = &s.ctxt
+		case "intr":
+			dst = &s.intr
+		case "processes":
+			dst = &s.processes
+		case "procs_running":
+			dst = &s.procsRunning
+		case "procs_blocked":
+			dst = &s.procsBlocked
+		default:
+			continue
+		}
+		*dst, _ = strconv.ParseUint(fields[1], 10, 64)
+	}
+	return s, scanner.Err()
+}
+
+// collectProcStat fills the context switch, interrupt and fork rates and
+// the runnable and blocked task counts.
+func (c *Collector) collectProcStat(metrics *CPUMetrics) {
+	now := time.Now()
+	cur, err := readProcStat(filepath.Join(c.opts.ProcfsRoot, "stat"))
+	if err != nil {
+		return
+	}
+
+	metrics.ProcsRunning = cur.procsRunning
+	metrics.ProcsBlocked = cur.procsBlocked
+	if !c.lastProcStatTime.IsZero() {
+		prev := c.lastProcStat
+		elapsed := now.Sub(c.lastProcStatTime).Seconds()
+		metrics.ContextSwitchRate = counterRate(prev.ctxt, cur.ctxt, elapsed)
+		metrics.InterruptRate = counterRate(prev.intr, cur.intr, elapsed)
+		metrics.ForkRate = counterRate(prev.processes, cur.processes, elapsed)
+	}
+	c.lastProcStat = cur
+	c.lastProcStatTime = now
+}
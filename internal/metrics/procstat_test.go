@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseProcStat(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  procStatCounters
+	}{
+		{
+			name: "full",
+			input: "cpu  10132153 290696 3084719 46828483 16683 0 25195 0 175628 0\n" +
+				"cpu0 1393280 32966 572056 13343292 6130 0 17875 0 23933 0\n" +
+				"intr 199292847 9 0 0 0 0 0 0 0 1 0 0 0 154 0 0 0 0 0 0\n" +
+				"ctxt 490291633\n" +
+				"btime 1700000000\n" +
+				"processes 2614729\n" +
+				"procs_running 3\n" +
+				"procs_blocked 1\n" +
+				"softirq 74318921 0 21366843 13 3085226 37469 0 2 24113042 0 25716326\n",
+			want: procStatCounters{ctxt: 490291633, intr: 199292847, processes: 2614729, procsRunning: 3, procsBlocked: 1},
+		},
+		{
+			// Fields missing from the file stay zero
+			name:  "partial",
+			input: "ctxt 42\nprocs_running 7\nprocs_blocked\n",
+			want:  procStatCounters{ctxt: 42, procsRunning: 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseProcStat(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("parseProcStat = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProcStatLongIntrLine(t *testing.T) {
+	// Large machines have thousands of IRQs on the intr line
+	intr := "intr 123456" + strings.Repeat(" 0", 100000)
+	got, err := parseProcStat(strings.NewReader(intr + "\nctxt 99\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.intr != 123456 || got.ctxt != 99 {
+		t.Errorf("intr %d ctxt %d, want 123456 and 99", got.intr, got.ctxt)
+	}
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import "sort"
+
+// ProcessNode is a process in the parent/child hierarchy together with the
+// totals for its whole subtree (itself plus all descendants).
+type ProcessNode struct {
+	Process     ProcessInfo
+	Children    []*ProcessNode
+	TreeCPU     float64
+	TreeRSS     uint64
+	TreeThreads int32
+	TreeCount   int
+}
+
+// BuildProcessTree links processes by PPID. Processes whose parent is not
+// in the list (init, kthreadd, or orphans seen mid-exit) become roots.
+// Roots and children are ordered by PID.
+func BuildProcessTree(procs []ProcessInfo) []*ProcessNode {
+	nodes := make(map[int32]*ProcessNode, len(procs))
+	for _, p := range procs {
+		nodes[p.PID] = &ProcessNode{Process: p}
+	}
+
+	var roots []*ProcessNode
+	for _, p := range procs {
+		node := nodes[p.PID]
+		parent, ok := nodes[p.PPID]
+		if !ok || p.PPID == p.PID {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	sortNodes(roots)
+	for _, root := range roots {
+		root.computeTotals()
+	}
+	return roots
+}
+
+func sortNodes(nodes []*ProcessNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Process.PID < nodes[j].Process.PID
+	})
+	for _, n := range nodes {
+		sortNodes(n.Children)
+	}
+}
+
+func (n *ProcessNode) computeTotals() {
+	n.TreeCPU = n.Process.CPUPercent
+	n.TreeRSS = n.Process.RSS
+	n.TreeThreads = n.Process.Threads
+	n.TreeCount = 1
+	for _, child := range n.Children {
+		child.computeTotals()
+		n.TreeCPU += child.TreeCPU
+		n.TreeRSS += child.TreeRSS
+		n.TreeThreads += child.TreeThreads
+		n.TreeCount += child.TreeCount
+	}
+}
+
+// Walk visits n and its descendants depth-first. Returning false from fn
+// skips the node's children.
+func (n *ProcessNode) Walk(fn func(node *ProcessNode, depth int) bool) {
+	n.walk(fn, 0)
+}
+
+func (n *ProcessNode) walk(fn func(node *ProcessNode, depth int) bool, depth int) {
+	if !fn(n, depth) {
+		return
+	}
+	for _, child := range n.Children {
+		child.walk(fn, depth+1)
+	}
+}
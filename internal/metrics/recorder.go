@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+)
+
+// ProcessUsage is the CPU time a process consumed while being recorded.
+type ProcessUsage struct {
+	PID     int32
+	Name    string
+	Cmdline string
+	CPUTime float64
+	PeakRSS uint64
+}
+
+// RunRecorder accumulates scoped metrics over the lifetime of a command so
+// a summary can be printed when it exits. It is safe for concurrent use.
+type RunRecorder struct {
+	mu        sync.Mutex
+	history   *History
+	samples   int
+	usageSum  float64
+	peakCPU   float64
+	peakRSS   uint64
+	processes map[int32]*ProcessUsage
+}
+
+// NewRunRecorder keeps up to historySize samples of the tree's CPU usage.
+func NewRunRecorder(historySize int) *RunRecorder {
+	return &RunRecorder{
+		history:   NewHistory(historySize, 1),
+		processes: make(map[int32]*ProcessUsage),
+	}
+}
+
+// Record adds one collection. Metrics without scope data are ignored.
+func (r *RunRecorder) Record(m *CPUMetrics) {
+	if m == nil || m.Scope == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	scope := m.Scope
+	r.history.Add(scope.CPUPercent)
+	r.samples++
+	r.usageSum += scope.CPUPercent
+	if scope.CPUPercent > r.peakCPU {
+		r.peakCPU = scope.CPUPercent
+	}
+	if scope.RSS > r.peakRSS {
+		r.peakRSS = scope.RSS
+	}
+
+	for _, p := range scope.Processes {
+		usage, ok := r.processes[p.PID]
+		if !ok {
+			usage = &ProcessUsage{PID: p.PID}
+			r.processes[p.PID] = usage
+		}
+		usage.Name = p.Name
+		usage.Cmdline = p.Cmdline
+		if p.CPUTime > usage.CPUTime {
+			usage.CPUTime = p.CPUTime
+		}
+		if p.RSS > usage.PeakRSS {
+			usage.PeakRSS = p.RSS
+		}
+	}
+}
+
+// RunStats summarizes what a RunRecorder saw. CPU percentages are relative
+// to one CPU, like /usr/bin/time.
+type RunStats struct {
+	Samples    int
+	PeakCPU    float64
+	AverageCPU float64
+	PeakRSS    uint64
+	History    []float64
+	// Busiest lists the processes of the tree by CPU time, busiest first
+	Busiest []ProcessUsage
+}
+
+// Stats returns the summary so far, keeping at most topN busiest processes.
+func (r *RunRecorder) Stats(topN int) RunStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := RunStats{
+		Samples: r.samples,
+		PeakCPU: r.peakCPU,
+		PeakRSS: r.peakRSS,
+		History: r.history.GetValues(),
+	}
+	if r.samples > 0 {
+		stats.AverageCPU = r.usageSum / float64(r.samples)
+	}
+
+	for _, p := range r.processes {
+		stats.Busiest = append(stats.Busiest, *p)
+	}
+	sort.Slice(stats.Busiest, func(i, j int) bool {
+		if stats.Busiest[i].CPUTime != stats.Busiest[j].CPUTime {
+			return stats.Busiest[i].CPUTime > stats.Busiest[j].CPUTime
+		}
+		return stats.Busiest[i].PID < stats.Busiest[j].PID
+	})
+	if len(stats.Busiest) > topN {
+		stats.Busiest = stats.Busiest[:topN]
+	}
+	return stats
+}
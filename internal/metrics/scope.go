@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"os"
+	"regexp"
+)
+
+// Scope narrows monitoring to processes matching a PID list or a name
+// pattern, together with all of their descendants.
+type Scope struct {
+	PIDs []int32
+	// Name is matched against the process name and full command line
+	Name *regexp.Regexp
+}
+
+// Active reports whether the scope selects anything at all.
+func (s Scope) Active() bool {
+	return len(s.PIDs) > 0 || s.Name != nil
+}
+
+func (s Scope) matches(p ProcessInfo, self map[int32]bool) bool {
+	for _, pid := range s.PIDs {
+		if p.PID == pid {
+			return true
+		}
+	}
+	// "cpu-monitor -name nginx" matches its own command line, and so do
+	// parents such as sudo, so the pattern skips the monitor's ancestry
+	if self[p.PID] {
+		return false
+	}
+	return s.Name != nil && (s.Name.MatchString(p.Name) || s.Name.MatchString(p.Cmdline))
+}
+
+// Select returns the processes matching the scope plus their descendants,
+// and the PIDs of the matching processes themselves. The monitor itself
+// is never selected.
+func (s Scope) Select(procs []ProcessInfo) (selected []ProcessInfo, roots []int32) {
+	return s.selectExcluding(procs, int32(os.Getpid()))
+}
+
+// selectExcluding is Select for a monitor running as pid self.
+func (s Scope) selectExcluding(procs []ProcessInfo, self int32) (selected []ProcessInfo, roots []int32) {
+	ancestry := selfAndAncestors(procs, self)
+	for _, root := range BuildProcessTree(procs) {
+		root.Walk(func(node *ProcessNode, depth int) bool {
+			if !s.matches(node.Process, ancestry) {
+				return true
+			}
+			roots = append(roots, node.Process.PID)
+			// Take the whole subtree and stop descending so nested
+			// matches aren't counted twice
+			node.Walk(func(n *ProcessNode, depth int) bool {
+				if n.Process.PID != self {
+					selected = append(selected, n.Process)
+				}
+				return true
+			})
+			return false
+		})
+	}
+	return selected, roots
+}
+
+// selfAndAncestors returns pid and every ancestor found in procs.
+func selfAndAncestors(procs []ProcessInfo, pid int32) map[int32]bool {
+	parents := make(map[int32]int32, len(procs))
+	for _, p := range procs {
+		parents[p.PID] = p.PPID
+	}
+	ancestry := make(map[int32]bool)
+	for pid > 0 && !ancestry[pid] {
+		ancestry[pid] = true
+		pid = parents[pid]
+	}
+	return ancestry
+}
+
+// ScopeMetrics aggregates the processes selected by a Scope.
+type ScopeMetrics struct {
+	// Usage is the share of all CPUs, comparable with TotalUsage
+	Usage float64
+	// CPUPercent is the sum of per-process CPU (100 = one full CPU)
+	CPUPercent  float64
+	CPUTime     float64
+	RSS         uint64
+	MemoryUsage float64
+	Roots       []int32
+	Processes   []ProcessInfo
+}
+
+// scopeMetrics summarizes the scoped processes. cpuCount converts the sum
+// of per-process usage into a share of the whole machine.
+func scopeMetrics(scope Scope, procs []ProcessInfo, cpuCount int, memoryTotal uint64) *ScopeMetrics {
+	selected, roots := scope.Select(procs)
+	sm := &ScopeMetrics{Roots: roots, Processes: selected}
+	for _, p := range selected {
+		sm.CPUPercent += p.CPUPercent
+		sm.CPUTime += p.CPUTime
+		sm.RSS += p.RSS
+	}
+	if cpuCount > 0 {
+		sm.Usage = sm.CPUPercent / float64(cpuCount)
+		if sm.Usage > 100 {
+			sm.Usage = 100
+		}
+	}
+	if memoryTotal > 0 {
+		sm.MemoryUsage = float64(sm.RSS) / float64(memoryTotal) * 100
+	}
+	return sm
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestScopeSelectSkipsMonitor(t *testing.T) {
+	procs := []ProcessInfo{
+		{PID: 1, Name: "init", Cmdline: "/sbin/init"},
+		{PID: 100, PPID: 1, Name: "sudo", Cmdline: "sudo cpu-monitor -name nginx"},
+		{PID: 101, PPID: 100, Name: "cpu-monitor", Cmdline: "cpu-monitor -name nginx"},
+		{PID: 200, PPID: 1, Name: "nginx", Cmdline: "nginx: master process"},
+		{PID: 201, PPID: 200, Name: "nginx", Cmdline: "nginx: worker process"},
+	}
+	pids := func(ps []ProcessInfo) []int32 {
+		var out []int32
+		for _, p := range ps {
+			out = append(out, p.PID)
+		}
+		slices.Sort(out)
+		return out
+	}
+
+	byName := Scope{Name: regexp.MustCompile("nginx")}
+	selected, roots := byName.selectExcluding(procs, 101)
+	if !slices.Equal(roots, []int32{200}) || !slices.Equal(pids(selected), []int32{200, 201}) {
+		t.Errorf("-name nginx: roots %v, selected %v; want [200], [200 201]", roots, pids(selected))
+	}
+
+	// Explicit PIDs still work on ancestors; only the monitor is left out
+	byPID := Scope{PIDs: []int32{1}}
+	selected, roots = byPID.selectExcluding(procs, 101)
+	if !slices.Equal(roots, []int32{1}) || !slices.Equal(pids(selected), []int32{1, 100, 200, 201}) {
+		t.Errorf("-pid 1: roots %v, selected %v; want [1], [1 100 200 201]", roots, pids(selected))
+	}
+}
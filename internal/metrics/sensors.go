@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// SensorReading is a single temperature sensor. Thresholds are 0 when the
+// sensor doesn't report them.
+type SensorReading struct {
+	Key         string
+	Temperature float64
+	High        float64
+	Critical    float64
+}
+
+// packageSensorKeys are tried in order when no sensor is configured. They
+// cover Intel coretemp, AMD k10temp/zenpower, ARM SoCs and macOS SMC keys.
+var packageSensorKeys = []string{
+	"coretemp_package_id_0",
+	"k10temp_tctl",
+	"k10temp_tdie",
+	"zenpower_tdie",
+	"cpu_thermal",
+	"coretemp_core_0",
+	"TC0P",
+	"CPU",
+}
+
+// coretempLabel matches the labels of coretemp hwmon inputs: "Package id 1"
+// or "Core 3".
+var coretempLabel = regexp.MustCompile(`^(Package id|Core) (\d+)$`)
+
+// readSensors returns every temperature sensor gopsutil can see. Partial
+// failures still yield the sensors that could be read.
+func readSensors(ctx context.Context) []SensorReading {
+	temps, _ := host.SensorsTemperaturesWithContext(ctx)
+	readings := make([]SensorReading, 0, len(temps))
+	for _, t := range temps {
+		readings = append(readings, SensorReading{
+			Key:         t.SensorKey,
+			Temperature: t.Temperature,
+			High:        t.High,
+			Critical:    t.Critical,
+		})
+	}
+	return readings
+}
+
+// selectPackageSensor picks the sensor that represents the CPU package.
+// A configured key wins when present; otherwise the well-known keys are
+// tried in order. Unrelated sensors (NVMe, ACPI, ...) are never used.
+func selectPackageSensor(readings []SensorReading, preferred string) (SensorReading, bool) {
+	byKey := make(map[string]SensorReading, len(readings))
+	for _, r := range readings {
+		if _, seen := byKey[r.Key]; !seen {
+			byKey[r.Key] = r
+		}
+	}
+
+	if preferred != "" {
+		if r, ok := byKey[preferred]; ok {
+			return r, true
+		}
+	}
+
+	for _, key := range packageSensorKeys {
+		if r, ok := byKey[key]; ok {
+			return r, true
+		}
+	}
+
+	return SensorReading{}, false
+}
+
+// coreKey identifies a physical core; core ids repeat in every package.
+type coreKey struct {
+	pkg  int
+	core int
+}
+
+// readCoretemp reads the per-core inputs of every coretemp hwmon device,
+// keyed by package and core. Each device covers one package, named by its
+// "Package id N" input; without one, the coretemp.N platform device the
+// hwmon belongs to is used.
+func readCoretemp(sysfsRoot string) map[coreKey]float64 {
+	temps := make(map[coreKey]float64)
+	hwmons, _ := filepath.Glob(filepath.Join(sysfsRoot, "class", "hwmon", "hwmon*"))
+	for _, dir := range hwmons {
+		if name, _ := readString(filepath.Join(dir, "name")); name != "coretemp" {
+			continue
+		}
+
+		pkg := 0
+		if device, err := os.Readlink(filepath.Join(dir, "device")); err == nil {
+			if n, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(device), "coretemp.")); err == nil {
+				pkg = n
+			}
+		}
+		cores := make(map[int]float64)
+		labels, _ := filepath.Glob(filepath.Join(dir, "temp*_label"))
+		for _, labelPath := range labels {
+			label, _ := readString(labelPath)
+			match := coretempLabel.FindStringSubmatch(label)
+			if match == nil {
+				continue
+			}
+			temp, err := readMilliCelsius(strings.TrimSuffix(labelPath, "_label") + "_input")
+			if err != nil {
+				continue
+			}
+			id, _ := strconv.Atoi(match[2])
+			if match[1] == "Package id" {
+				pkg = id
+			} else {
+				cores[id] = temp
+			}
+		}
+		for core, temp := range cores {
+			temps[coreKey{pkg, core}] = temp
+		}
+	}
+	return temps
+}
+
+// perCoreTemperatures maps coretemp readings onto logical CPUs by the
+// package and physical core of each CPU. topo is aligned with cpuNames;
+// CPUs without a reading report 0.
+func perCoreTemperatures(sysfsRoot string, cpuNames []string, topo []CPUTopology) []float64 {
+	coreTemps := readCoretemp(sysfsRoot)
+	if len(coreTemps) == 0 {
+		return nil
+	}
+
+	temps := make([]float64, len(cpuNames))
+	for i, name := range cpuNames {
+		key := coreKey{}
+		if i < len(topo) {
+			key = coreKey{topo[i].Package, topo[i].Core}
+		} else if n, ok := cpuNumber(name); ok {
+			// Without topology assume logical CPU N is core N
+			key.core = n
+		} else {
+			continue
+		}
+		temps[i] = coreTemps[key]
+	}
+	return temps
+}
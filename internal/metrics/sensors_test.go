@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestPerCoreTemperaturesMultiSocket(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"class/hwmon/hwmon1/name":         "coretemp\n",
+		"class/hwmon/hwmon1/temp1_label":  "Package id 0\n",
+		"class/hwmon/hwmon1/temp1_input":  "50000\n",
+		"class/hwmon/hwmon1/temp2_label":  "Core 0\n",
+		"class/hwmon/hwmon1/temp2_input":  "40000\n",
+		"class/hwmon/hwmon1/temp10_label": "Core 1\n",
+		"class/hwmon/hwmon1/temp10_input": "41000\n",
+		"class/hwmon/hwmon2/name":         "coretemp\n",
+		"class/hwmon/hwmon2/temp1_label":  "Package id 1\n",
+		"class/hwmon/hwmon2/temp1_input":  "60000\n",
+		"class/hwmon/hwmon2/temp2_label":  "Core 0\n",
+		"class/hwmon/hwmon2/temp2_input":  "70000\n",
+		"class/hwmon/hwmon2/temp3_label":  "Core 1\n",
+		"class/hwmon/hwmon2/temp3_input":  "71000\n",
+		"class/hwmon/hwmon3/name":         "nvme\n",
+		"class/hwmon/hwmon3/temp1_label":  "Core 0\n",
+		"class/hwmon/hwmon3/temp1_input":  "99000\n",
+	})
+	topo := []CPUTopology{
+		{CPU: 0, Package: 0, Core: 0},
+		{CPU: 1, Package: 0, Core: 1},
+		{CPU: 2, Package: 1, Core: 0},
+		{CPU: 3, Package: 1, Core: 1},
+		{CPU: 4, Package: 1, Core: 5}, // no reading
+	}
+
+	got := perCoreTemperatures(root, []string{"cpu0", "cpu1", "cpu2", "cpu3", "cpu4"}, topo)
+	want := []float64{40, 41, 70, 71, 0}
+	if !slices.Equal(got, want) {
+		t.Fatalf("perCoreTemperatures = %v, want %v", got, want)
+	}
+}
+
+func TestReadCoretempPackageFromDevice(t *testing.T) {
+	// Without a "Package id" input the coretemp.N device names the package
+	root := writeTree(t, map[string]string{
+		"class/hwmon/hwmon4/name":           "coretemp\n",
+		"class/hwmon/hwmon4/temp2_label":    "Core 3\n",
+		"class/hwmon/hwmon4/temp2_input":    "55500\n",
+		"devices/platform/coretemp.1/.keep": "",
+	})
+	if err := os.Symlink(filepath.Join(root, "devices/platform/coretemp.1"), filepath.Join(root, "class/hwmon/hwmon4/device")); err != nil {
+		t.Fatal(err)
+	}
+
+	temps := readCoretemp(root)
+	if got := temps[coreKey{pkg: 1, core: 3}]; got != 55.5 {
+		t.Errorf("package 1 core 3 = %v, want 55.5 (readings: %v)", got, temps)
+	}
+}
+
+func TestPerCoreTemperaturesWithoutCoretemp(t *testing.T) {
+	if got := perCoreTemperatures(t.TempDir(), []string{"cpu0"}, nil); got != nil {
+		t.Errorf("perCoreTemperatures without coretemp = %v, want nil", got)
+	}
+}
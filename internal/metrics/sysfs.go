@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Default locations of the kernel pseudo filesystems. Collector options can
+// point these at fixture trees for testing.
+const (
+	DefaultSysfsRoot  = "/sys"
+	DefaultProcfsRoot = "/proc"
+	DefaultCgroupRoot = "/sys/fs/cgroup"
+)
+
+// readString returns the trimmed contents of a small sysfs or procfs file.
+func readString(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readUint parses a file holding a single unsigned integer.
+func readUint(path string) (uint64, error) {
+	s, err := readString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// counterRate converts two readings of a monotonic counter into a per-second
+// rate. Counter resets (cur < prev) yield 0.
+func counterRate(prev, cur uint64, elapsed float64) float64 {
+	if elapsed <= 0 || cur < prev {
+		return 0
+	}
+	return float64(cur-prev) / elapsed
+}
+
+// parseCPUList parses the kernel's CPU list format, e.g. "0-3,8,10-11".
+func parseCPUList(s string) ([]int, error) {
+	var cpus []int
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil {
+				return nil, err
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ThermalZone is one kernel thermal zone with its trip points.
+type ThermalZone struct {
+	// Name is the sysfs directory ("thermal_zone0"), Type what the
+	// driver calls it ("x86_pkg_temp", "acpitz")
+	Name        string
+	Type        string
+	Temperature float64
+	Trips       []TripPoint
+}
+
+// TripPoint is a temperature at which the kernel acts on a zone: "passive"
+// throttles, "critical" shuts down.
+type TripPoint struct {
+	Type        string
+	Temperature float64
+}
+
+// NextTrip returns the lowest trip point the zone has not reached yet.
+func (z ThermalZone) NextTrip() (TripPoint, bool) {
+	var next TripPoint
+	found := false
+	for _, t := range z.Trips {
+		if t.Temperature > z.Temperature && (!found || t.Temperature < next.Temperature) {
+			next, found = t, true
+		}
+	}
+	return next, found
+}
+
+// ThrottleMetrics summarizes the thermal_throttle counters of the CPUs.
+type ThrottleMetrics struct {
+	// Available is false when no CPU exposes thermal_throttle counters
+	// (non-Intel CPUs, most VMs)
+	Available bool
+	// Throttle events since the previous collection; the package counter
+	// is shared by the CPUs of a socket and counted once per socket
+	CoreEvents    uint64
+	PackageEvents uint64
+	// Counts since boot
+	CoreTotal    uint64
+	PackageTotal uint64
+	// ThrottledCPUs are the CPUs whose core counter rose
+	ThrottledCPUs []int
+}
+
+// Events is the number of core and package throttle events since the
+// previous collection.
+func (t ThrottleMetrics) Events() uint64 {
+	return t.CoreEvents + t.PackageEvents
+}
+
+// throttleCounters is one reading of the thermal_throttle counters, keyed
+// by CPU name and by package id.
+type throttleCounters struct {
+	core    map[string]uint64
+	pkg     map[int]uint64
+	present bool
+}
+
+// readThermalZones reads every <sysfs>/class/thermal/thermal_zone*. Zones
+// whose temperature can't be read (disabled ACPI zones return EIO or
+// ENODATA) are skipped.
+func readThermalZones(sysfsRoot string) []ThermalZone {
+	dirs, _ := filepath.Glob(filepath.Join(sysfsRoot, "class", "thermal", "thermal_zone*"))
+	zones := make([]ThermalZone, 0, len(dirs))
+	for _, dir := range dirs {
+		temp, err := readMilliCelsius(filepath.Join(dir, "temp"))
+		if err != nil {
+			continue
+		}
+		zone := ThermalZone{Name: filepath.Base(dir), Temperature: temp}
+		zone.Type, _ = readString(filepath.Join(dir, "type"))
+		for i := 0; ; i++ {
+			tripTemp, err := readMilliCelsius(filepath.Join(dir, fmt.Sprintf("trip_point_%d_temp", i)))
+			if err != nil {
+				break
+			}
+			tripType, _ := readString(filepath.Join(dir, fmt.Sprintf("trip_point_%d_type", i)))
+			zone.Trips = append(zone.Trips, TripPoint{Type: tripType, Temperature: tripTemp})
+		}
+		zones = append(zones, zone)
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		a, _ := strconv.Atoi(strings.TrimPrefix(zones[i].Name, "thermal_zone"))
+		b, _ := strconv.Atoi(strings.TrimPrefix(zones[j].Name, "thermal_zone"))
+		return a < b
+	})
+	return zones
+}
+
+// readMilliCelsius reads a sysfs temperature in millidegrees Celsius. The
+// value is signed; sensors below freezing report negative numbers.
+func readMilliCelsius(path string) (float64, error) {
+	s, err := readString(path)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return float64(v) / 1000, nil
+}
+
+// readThrottleCounters reads thermal_throttle/core_throttle_count for each
+// named CPU and package_throttle_count once per package. topo is aligned
+// with cpuNames; without it every CPU is assumed to be in package 0.
+func readThrottleCounters(sysfsRoot string, cpuNames []string, topo []CPUTopology) throttleCounters {
+	counters := throttleCounters{
+		core: make(map[string]uint64, len(cpuNames)),
+		pkg:  make(map[int]uint64),
+	}
+	for i, name := range cpuNames {
+		dir := filepath.Join(cpuSysfsDir(sysfsRoot, name), "thermal_throttle")
+		if v, err := readUint(filepath.Join(dir, "core_throttle_count")); err == nil {
+			counters.core[name] = v
+			counters.present = true
+		}
+		pkg := 0
+		if i < len(topo) {
+			pkg = topo[i].Package
+		}
+		if _, seen := counters.pkg[pkg]; seen {
+			continue
+		}
+		if v, err := readUint(filepath.Join(dir, "package_throttle_count")); err == nil {
+			counters.pkg[pkg] = v
+			counters.present = true
+		}
+	}
+	return counters
+}
+
+// collectThrottle turns the throttle counters into events since the
+// previous collection. The first collection reports totals only.
+func (c *Collector) collectThrottle(metrics *CPUMetrics, cpuNames []string) {
+	cur := readThrottleCounters(c.opts.SysfsRoot, cpuNames, c.topology)
+	if !cur.present {
+		return
+	}
+	t := &metrics.Throttle
+	t.Available = true
+	for i, name := range cpuNames {
+		v, ok := cur.core[name]
+		if !ok {
+			continue
+		}
+		t.CoreTotal += v
+		if prev, seen := c.lastThrottle.core[name]; seen && v > prev {
+			t.CoreEvents += v - prev
+			if i < len(metrics.CPUIDs) {
+				t.ThrottledCPUs = append(t.ThrottledCPUs, metrics.CPUIDs[i])
+			}
+		}
+	}
+	for pkg, v := range cur.pkg {
+		t.PackageTotal += v
+		if prev, seen := c.lastThrottle.pkg[pkg]; seen && v > prev {
+			t.PackageEvents += v - prev
+		}
+	}
+	c.lastThrottle = cur
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CPUTopology places one logical CPU in the machine.
+type CPUTopology struct {
+	// CPU is the kernel's logical CPU number
+	CPU int
+	// Package is the physical socket, Node the NUMA node (0 without NUMA)
+	// and Core the physical core id within the package
+	Package int
+	Node    int
+	Core    int
+	// Siblings are the logical CPUs sharing the physical core (SMT),
+	// including CPU itself
+	Siblings []int
+}
+
+// TopologySummary counts the distinct sockets, NUMA nodes and physical
+// cores in a topology.
+func TopologySummary(topo []CPUTopology) (sockets, nodes, cores int) {
+	socketSet := make(map[int]bool)
+	nodeSet := make(map[int]bool)
+	coreSet := make(map[[2]int]bool)
+	for _, t := range topo {
+		socketSet[t.Package] = true
+		nodeSet[t.Node] = true
+		coreSet[[2]int{t.Package, t.Core}] = true
+	}
+	return len(socketSet), len(nodeSet), len(coreSet)
+}
+
+// cpuNumber extracts N from a CPU name such as "cpu12".
+func cpuNumber(name string) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimPrefix(name, "cpu"))
+	return n, err == nil
+}
+
+// readNUMANodes maps each CPU to its NUMA node from <sysfs>/devices/system/node.
+func readNUMANodes(sysfsRoot string) map[int]int {
+	nodes := make(map[int]int)
+	dir := filepath.Join(sysfsRoot, "devices", "system", "node")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nodes
+	}
+	for _, e := range entries {
+		id, err := strconv.Atoi(strings.TrimPrefix(e.Name(), "node"))
+		if err != nil || !strings.HasPrefix(e.Name(), "node") {
+			continue
+		}
+		list, err := readString(filepath.Join(dir, e.Name(), "cpulist"))
+		if err != nil {
+			continue
+		}
+		cpus, err := parseCPUList(list)
+		if err != nil {
+			continue
+		}
+		for _, cpu := range cpus {
+			nodes[cpu] = id
+		}
+	}
+	return nodes
+}
+
+// readTopology reads the topology of each named CPU. Where sysfs has no
+// topology (e.g. some containers), each CPU is treated as its own core.
+func readTopology(sysfsRoot string, cpuNames []string) []CPUTopology {
+	nodes := readNUMANodes(sysfsRoot)
+	topo := make([]CPUTopology, len(cpuNames))
+	for i, name := range cpuNames {
+		cpu, ok := cpuNumber(name)
+		if !ok {
+			cpu = i
+		}
+		t := CPUTopology{CPU: cpu, Core: cpu, Siblings: []int{cpu}, Node: nodes[cpu]}
+
+		dir := filepath.Join(cpuSysfsDir(sysfsRoot, name), "topology")
+		if v, err := readString(filepath.Join(dir, "physical_package_id")); err == nil {
+			t.Package, _ = strconv.Atoi(v)
+		}
+		if v, err := readString(filepath.Join(dir, "core_id")); err == nil {
+			t.Core, _ = strconv.Atoi(v)
+		}
+		if v, err := readString(filepath.Join(dir, "thread_siblings_list")); err == nil {
+			if siblings, err := parseCPUList(v); err == nil && len(siblings) > 0 {
+				t.Siblings = siblings
+			}
+		}
+		topo[i] = t
+	}
+	return topo
+}
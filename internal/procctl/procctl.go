@@ -0,0 +1,28 @@
+// Package procctl sends signals to and renices processes selected in the
+// UI. It takes plain PIDs so callers can exercise it against processes they
+// start themselves.
+package procctl
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Signal is a signal offered in the process action menu.
+type Signal struct {
+	Name   string
+	Signal syscall.Signal
+}
+
+// Nice values accepted by Renice.
+const (
+	MinNice = -20
+	MaxNice = 19
+)
+
+// ErrInvalidPID is returned for PIDs that would address process groups or
+// every process (0 and negative values in kill(2)).
+var ErrInvalidPID = errors.New("invalid pid")
+
+// ErrUnsupported is returned on platforms without signals and nice values.
+var ErrUnsupported = errors.New("not supported on this platform")
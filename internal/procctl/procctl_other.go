@@ -0,0 +1,24 @@
+//go:build !unix
+
+package procctl
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// Supported reports whether signals and renice work on this platform.
+const Supported = false
+
+// Signals is empty where processes can't be signalled.
+var Signals []Signal
+
+// SendSignal always fails with ErrUnsupported.
+func SendSignal(pid int, sig syscall.Signal) error {
+	return fmt.Errorf("signal %d: %w", pid, ErrUnsupported)
+}
+
+// Renice always fails with ErrUnsupported.
+func Renice(pid int, nice int) error {
+	return fmt.Errorf("renice %d: %w", pid, ErrUnsupported)
+}
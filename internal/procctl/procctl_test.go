@@ -0,0 +1,97 @@
+//go:build linux || darwin
+
+package procctl
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"testing"
+)
+
+// startSleep starts a child that lives until it is signalled, and kills it
+// when the test ends.
+func startSleep(t *testing.T) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	return cmd
+}
+
+// waitState waits for the child to stop, continue or exit.
+func waitState(t *testing.T, pid int) syscall.WaitStatus {
+	t.Helper()
+	var ws syscall.WaitStatus
+	if _, err := syscall.Wait4(pid, &ws, syscall.WUNTRACED|syscall.WCONTINUED, nil); err != nil {
+		t.Fatalf("wait4(%d): %v", pid, err)
+	}
+	return ws
+}
+
+func TestSendSignalStopContTerm(t *testing.T) {
+	pid := startSleep(t).Process.Pid
+
+	if err := SendSignal(pid, syscall.SIGSTOP); err != nil {
+		t.Fatalf("SIGSTOP: %v", err)
+	}
+	if ws := waitState(t, pid); !ws.Stopped() || ws.StopSignal() != syscall.SIGSTOP {
+		t.Fatalf("after SIGSTOP: status %#x, want stopped", ws)
+	}
+
+	if err := SendSignal(pid, syscall.SIGCONT); err != nil {
+		t.Fatalf("SIGCONT: %v", err)
+	}
+	if ws := waitState(t, pid); !ws.Continued() {
+		t.Fatalf("after SIGCONT: status %#x, want continued", ws)
+	}
+
+	if err := SendSignal(pid, syscall.SIGTERM); err != nil {
+		t.Fatalf("SIGTERM: %v", err)
+	}
+	if ws := waitState(t, pid); !ws.Signaled() || ws.Signal() != syscall.SIGTERM {
+		t.Fatalf("after SIGTERM: status %#x, want killed by SIGTERM", ws)
+	}
+}
+
+func TestRenice(t *testing.T) {
+	pid := startSleep(t).Process.Pid
+
+	// Raising the nice value never needs privileges
+	if err := Renice(pid, 10); err != nil {
+		t.Fatalf("Renice: %v", err)
+	}
+	prio, err := syscall.Getpriority(syscall.PRIO_PROCESS, pid)
+	if err != nil {
+		t.Fatalf("getpriority: %v", err)
+	}
+	nice := prio
+	if runtime.GOOS == "linux" {
+		// The raw Linux syscall returns 20 - nice
+		nice = 20 - prio
+	}
+	if nice != 10 {
+		t.Errorf("nice after Renice(10) = %d", nice)
+	}
+
+	if err := Renice(pid, MaxNice+1); err == nil {
+		t.Errorf("Renice(%d) succeeded, want a range error", MaxNice+1)
+	}
+}
+
+func TestInvalidPID(t *testing.T) {
+	for _, pid := range []int{0, -1} {
+		if err := SendSignal(pid, syscall.SIGTERM); !errors.Is(err, ErrInvalidPID) {
+			t.Errorf("SendSignal(%d) = %v, want ErrInvalidPID", pid, err)
+		}
+		if err := Renice(pid, 5); !errors.Is(err, ErrInvalidPID) {
+			t.Errorf("Renice(%d) = %v, want ErrInvalidPID", pid, err)
+		}
+	}
+}
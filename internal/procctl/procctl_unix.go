@@ -0,0 +1,45 @@
+//go:build unix
+
+package procctl
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// Supported reports whether signals and renice work on this platform.
+const Supported = true
+
+// Signals lists the signals the action menu offers, in display order.
+var Signals = []Signal{
+	{"SIGTERM", syscall.SIGTERM},
+	{"SIGKILL", syscall.SIGKILL},
+	{"SIGSTOP", syscall.SIGSTOP},
+	{"SIGCONT", syscall.SIGCONT},
+}
+
+// SendSignal delivers sig to a single process.
+func SendSignal(pid int, sig syscall.Signal) error {
+	if pid <= 0 {
+		return fmt.Errorf("signal %d: %w", pid, ErrInvalidPID)
+	}
+	if err := syscall.Kill(pid, sig); err != nil {
+		return fmt.Errorf("signal %d: %w", pid, err)
+	}
+	return nil
+}
+
+// Renice sets the scheduling priority of a single process. Lowering the
+// nice value usually requires root (EACCES/EPERM otherwise).
+func Renice(pid int, nice int) error {
+	if pid <= 0 {
+		return fmt.Errorf("renice %d: %w", pid, ErrInvalidPID)
+	}
+	if nice < MinNice || nice > MaxNice {
+		return fmt.Errorf("renice %d: nice value %d out of range %d..%d", pid, nice, MinNice, MaxNice)
+	}
+	if err := syscall.Setpriority(syscall.PRIO_PROCESS, pid, nice); err != nil {
+		return fmt.Errorf("renice %d: %w", pid, err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/user/cpu-monitor/internal/config"
+	"github.com/user/cpu-monitor/internal/procctl"
+)
+
+// actionMode is the step of the process action dialog that is open.
+type actionMode int
+
+const (
+	actionNone actionMode = iota
+	actionSignalMenu
+	actionReniceMenu
+	actionConfirm
+)
+
+// processAction describes the operation being prepared in the dialog.
+type processAction struct {
+	mode   actionMode
+	pid    int32
+	name   string
+	signal int // index into procctl.Signals
+	nice   int
+	renice bool // confirm step applies a renice instead of a signal
+}
+
+// actionResultMsg reports the outcome of a signal or renice.
+type actionResultMsg struct {
+	status string
+	err    error
+}
+
+func signalCmd(pid int32, sig procctl.Signal) tea.Cmd {
+	return func() tea.Msg {
+		if err := procctl.SendSignal(int(pid), sig.Signal); err != nil {
+			return actionResultMsg{err: err}
+		}
+		return actionResultMsg{status: fmt.Sprintf("Sent %s to %d", sig.Name, pid)}
+	}
+}
+
+func reniceCmd(pid int32, nice int) tea.Cmd {
+	return func() tea.Msg {
+		if err := procctl.Renice(int(pid), nice); err != nil {
+			return actionResultMsg{err: err}
+		}
+		return actionResultMsg{status: fmt.Sprintf("Set nice of %d to %d", pid, nice)}
+	}
+}
+
+// actionKeysHint lists the action keys for footers, or nothing where
+// procctl can't signal processes.
+func actionKeysHint() string {
+	if !procctl.Supported {
+		return ""
+	}
+	return "  k: signal  n: renice"
+}
+
+// openAction starts the dialog for the selected process.
+func (m *Model) openAction(mode actionMode) {
+	for _, p := range m.metrics.Processes {
+		if p.PID != m.selectedPID {
+			continue
+		}
+		m.action = processAction{
+			mode: mode,
+			pid:  p.PID,
+			name: p.Name,
+			nice: int(p.Nice),
+		}
+		m.statusLine = ""
+		m.statusErr = false
+		return
+	}
+}
+
+// handleActionKey handles keys while the action dialog is open. The dialog
+// is modal, so every key is consumed.
+func (m *Model) handleActionKey(key string) tea.Cmd {
+	switch key {
+	case "esc", "q":
+		m.action.mode = actionNone
+		return nil
+	}
+
+	switch m.action.mode {
+	case actionSignalMenu:
+		switch key {
+		case "up":
+			if m.action.signal > 0 {
+				m.action.signal--
+			}
+		case "down":
+			if m.action.signal < len(procctl.Signals)-1 {
+				m.action.signal++
+			}
+		case "1", "2", "3", "4":
+			m.action.signal = int(key[0] - '1')
+			m.action.mode = actionConfirm
+		case "enter":
+			m.action.mode = actionConfirm
+		}
+
+	case actionReniceMenu:
+		switch key {
+		case "up", "+":
+			if m.action.nice < procctl.MaxNice {
+				m.action.nice++
+			}
+		case "down", "-":
+			if m.action.nice > procctl.MinNice {
+				m.action.nice--
+			}
+		case "enter":
+			m.action.renice = true
+			m.action.mode = actionConfirm
+		}
+
+	case actionConfirm:
+		switch key {
+		case "y", "enter":
+			m.action.mode = actionNone
+			if m.action.renice {
+				return reniceCmd(m.action.pid, m.action.nice)
+			}
+			return signalCmd(m.action.pid, procctl.Signals[m.action.signal])
+		case "n":
+			m.action.mode = actionNone
+		}
+	}
+	return nil
+}
+
+// actionDialogHeight is the number of lines the open dialog occupies.
+func (m Model) actionDialogHeight() int {
+	switch m.action.mode {
+	case actionSignalMenu:
+		return len(procctl.Signals) + 4
+	case actionReniceMenu, actionConfirm:
+		return 5
+	}
+	return 0
+}
+
+func (m Model) renderActionDialog() string {
+	target := fmt.Sprintf("%d (%s)", m.action.pid, m.action.name)
+
+	var lines []string
+	switch m.action.mode {
+	case actionSignalMenu:
+		lines = append(lines, "Send signal to "+target)
+		for i, sig := range procctl.Signals {
+			line := fmt.Sprintf(" %d  %s", i+1, sig.Name)
+			if i == m.action.signal {
+				line = SelectedRowStyle.Render(line + " ")
+			}
+			lines = append(lines, line)
+		}
+		lines = append(lines, HelpStyle.Render("↑/↓ or 1-4 choose  enter select  esc cancel"))
+
+	case actionReniceMenu:
+		lines = append(lines,
+			"Renice "+target,
+			fmt.Sprintf("nice: %s", KeyStyle.Render(fmt.Sprintf("%3d", m.action.nice))),
+			HelpStyle.Render(fmt.Sprintf("↑/↓ or +/- adjust (%d..%d)  enter select  esc cancel", procctl.MinNice, procctl.MaxNice)),
+		)
+
+	case actionConfirm:
+		question := fmt.Sprintf("Send %s to %s?", procctl.Signals[m.action.signal].Name, target)
+		if m.action.renice {
+			question = fmt.Sprintf("Set nice of %s to %d?", target, m.action.nice)
+		}
+		lines = append(lines,
+			PauseStyle.UnsetBlink().Render(question),
+			"",
+			HelpStyle.Render("y confirm  n/esc cancel"),
+		)
+	}
+
+	// The box adds a border line above and below the content
+	return CreateBox("", strings.Join(lines, "\n"), m.width-4, len(lines), config.Colors.NeonPurple)
+}
+
+// renderStatusLine shows the result of the last process action.
+func (m Model) renderStatusLine() string {
+	if m.statusLine == "" {
+		return ""
+	}
+	if m.statusErr {
+		return RedStyle.Render(m.statusLine) + "  "
+	}
+	return GreenStyle.Render(m.statusLine) + "  "
+}
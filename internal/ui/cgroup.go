@@ -0,0 +1,21 @@
+package ui
+
+import "fmt"
+
+// renderCgroupBar shows the monitor's own cgroup usage against its CPU
+// quota, with a summary line that flags recent throttling.
+func (m Model) renderCgroupBar() string {
+	cg := m.metrics.Cgroup
+	bar := CreateCPUBar("Cgroup CPU", cg.Usage, m.width)
+
+	quota := "no quota"
+	if cg.QuotaCPUs > 0 {
+		quota = fmt.Sprintf("quota %.2f CPUs", cg.QuotaCPUs)
+	}
+	summary := ReferenceStyle.Render(fmt.Sprintf("cgroup %s  %s  using %.2f CPUs  throttled %.1f%% of periods (%d total, %s)",
+		truncateString(cg.Path, 48), quota, cg.CPUsUsed, cg.ThrottledPercent, cg.NrThrottled, formatDuration(cg.ThrottledTime)))
+	if cg.ThrottledRecently {
+		summary += "  " + RedStyle.Render("▲ THROTTLED")
+	}
+	return bar + "\n" + summary
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/user/cpu-monitor/internal/config"
+	"github.com/user/cpu-monitor/internal/metrics"
+)
+
+// cgroupsMsg delivers a background walk of the cgroup hierarchy.
+type cgroupsMsg struct {
+	groups []metrics.CgroupUsage
+	err    error
+}
+
+func cgroupsCmd(collector *metrics.CgroupCollector) tea.Cmd {
+	return func() tea.Msg {
+		groups, err := collector.Collect()
+		return cgroupsMsg{groups: groups, err: err}
+	}
+}
+
+func (m *Model) applyCgroups(msg cgroupsMsg) {
+	m.collectingCgroups = false
+	m.cgroups = msg.groups
+	m.cgroupsErr = msg.err
+}
+
+// Column layout shared by the header and rows
+const cgroupRowFormat = "%6s %9s %5s %-24s %s"
+
+// renderCgroupsScreen ranks cgroups by CPU usage, like systemd-cgtop. A
+// group's figures include all of its children.
+func (m Model) renderCgroupsScreen() string {
+	if m.cgroupsErr != nil {
+		return DimGrayStyle.Render(fmt.Sprintf("Cannot read cgroups under %s: %v", m.config.CgroupRoot, m.cgroupsErr))
+	}
+	if m.cgroups == nil {
+		return DimGrayStyle.Render("Collecting cgroups...")
+	}
+	if len(m.cgroups) == 0 {
+		return DimGrayStyle.Render(fmt.Sprintf("No cgroup v2 groups with CPU accounting under %s", m.config.CgroupRoot))
+	}
+
+	// Header, system info, the blank line, table header and footer
+	rows := m.height - 6
+	if rows < 1 {
+		rows = 1
+	}
+	if rows > len(m.cgroups) {
+		rows = len(m.cgroups)
+	}
+
+	var b strings.Builder
+	header := fmt.Sprintf(cgroupRowFormat, "CPU%▼", "MEM", "THR%", "NAME", "PATH")
+	b.WriteString(ProcessHeaderStyle.Render(padRight(header, m.width)))
+	b.WriteString("\n")
+
+	for _, g := range m.cgroups[:rows] {
+		cpuStyle := lipgloss.NewStyle().Foreground(config.GetCPUColor(g.CPUPercent))
+		if g.CPUPercent < 0.1 {
+			cpuStyle = DimGrayStyle
+		}
+		thrStyle := DimGrayStyle
+		if g.ThrottledPercent > 0 {
+			thrStyle = RedStyle
+		}
+		mem := "-"
+		if g.MemoryCurrent > 0 {
+			mem = formatBytes(g.MemoryCurrent)
+		}
+		line := fmt.Sprintf("%s %9s %s %-24s %s",
+			cpuStyle.Render(fmt.Sprintf("%6.1f", g.CPUPercent)),
+			mem,
+			thrStyle.Render(fmt.Sprintf("%5.1f", g.ThrottledPercent)),
+			KeyStyle.Render(fmt.Sprintf("%-24s", truncateString(g.Name, 24))),
+			HelpStyle.Render(truncateString(g.Path, max(m.width-48, 8))),
+		)
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString(HelpStyle.Render(fmt.Sprintf(
+		"%d cgroups under %s (depth ≤ 3)  CPU%%: 100 = one CPU, includes child groups  THR%%: periods throttled",
+		len(m.cgroups), m.config.CgroupRoot)))
+
+	return b.String()
+}
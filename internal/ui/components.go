@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/user/cpu-monitor/internal/config"
+	"github.com/user/cpu-monitor/internal/metrics"
 )
 
 // Pre-rendered brackets for progress bars
@@ -62,7 +63,112 @@ func CreateProgressBar(percentage float64, width int, color lipgloss.Color) stri
 	return openBracket + bar.String() + closeBracket
 }
 
+// BarSegment is one colored slice of a segmented bar.
+type BarSegment struct {
+	Percentage float64
+	Style      lipgloss.Style
+}
+
+// CreateSegmentedBar draws segments side by side in a bracketed bar. Segment
+// boundaries are rounded cumulatively so small slices don't drift the total.
+func CreateSegmentedBar(segments []BarSegment, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	barWidth := width - 2
+	if barWidth <= 0 {
+		return openBracket + closeBracket
+	}
+
+	var bar strings.Builder
+	cumulative := 0.0
+	pos := 0
+	for _, seg := range segments {
+		if seg.Percentage <= 0 {
+			continue
+		}
+		cumulative += seg.Percentage
+		end := int(float64(barWidth)*cumulative/100 + 0.5)
+		if end > barWidth {
+			end = barWidth
+		}
+		if end > pos {
+			bar.WriteString(seg.Style.Render(strings.Repeat("|", end-pos)))
+			pos = end
+		}
+	}
+	if pos < barWidth {
+		bar.WriteString(strings.Repeat(" ", barWidth-pos))
+	}
+
+	return openBracket + bar.String() + closeBracket
+}
+
+// CPUStateSegments maps a CPU breakdown to bar segments in display order.
+// Iowait is idle time and left out, so the fill matches Busy.
+func CPUStateSegments(b metrics.CPUBreakdown) []BarSegment {
+	return []BarSegment{
+		{b.User, UserStateStyle},
+		{b.Nice, NiceStateStyle},
+		{b.System, SystemStateStyle},
+		{b.Irq, IrqStateStyle},
+		{b.Softirq, SoftirqStateStyle},
+		{b.Steal, StealStateStyle},
+		{b.Guest + b.GuestNice, GuestStateStyle},
+	}
+}
+
+// CreateCPUStateLegend renders the share of each CPU state in its bar
+// color, dropping the entries that don't fit in width. Iowait comes last,
+// marked as idle since the bars don't include it.
+func CreateCPUStateLegend(b metrics.CPUBreakdown, width int) string {
+	states := []struct {
+		name  string
+		value float64
+		style lipgloss.Style
+	}{
+		{"usr", b.User, UserStateStyle},
+		{"nice", b.Nice, NiceStateStyle},
+		{"sys", b.System, SystemStateStyle},
+		{"irq", b.Irq, IrqStateStyle},
+		{"sirq", b.Softirq, SoftirqStateStyle},
+		{"steal", b.Steal, StealStateStyle},
+		{"guest", b.Guest + b.GuestNice, GuestStateStyle},
+		{"idle: iowait", b.Iowait, IowaitStateStyle},
+	}
+
+	var legend strings.Builder
+	used := 0
+	for i, st := range states {
+		part := fmt.Sprintf("%s %.1f%%", st.name, st.value)
+		if i > 0 {
+			part = "  " + part
+		}
+		if used+len(part) > width {
+			break
+		}
+		used += len(part)
+		legend.WriteString(st.style.Render(part))
+	}
+	return legend.String()
+}
+
+// GraphOptions adds overlays to CreateASCIIGraphWithOptions.
+type GraphOptions struct {
+	// Reference is drawn as a dotted line above the bars, aligned with
+	// values by their most recent sample
+	Reference []float64
+	// Markers flags samples, aligned like Reference, with a ▼ in the top
+	// row
+	Markers []bool
+}
+
 func CreateASCIIGraph(values []float64, width, height int) string {
+	return CreateASCIIGraphWithOptions(values, width, height, GraphOptions{})
+}
+
+func CreateASCIIGraphWithOptions(values []float64, width, height int, opts GraphOptions) string {
 	if len(values) == 0 || width <= 0 || height <= 0 {
 		return ""
 	}
@@ -120,6 +226,37 @@ func CreateASCIIGraph(values []float64, width, height int) string {
 		}
 	}
 
+	// Overlay the reference line wherever it is above the bars
+	if len(opts.Reference) > 0 {
+		refStart := len(opts.Reference) - (len(values) - startIdx)
+		for x := 0; x < graphWidth && startIdx+x < len(values); x++ {
+			ri := refStart + x
+			if ri < 0 || ri >= len(opts.Reference) {
+				continue
+			}
+			refHeight := int(opts.Reference[ri]*float64(height)/100 + 0.5)
+			if refHeight < 1 {
+				refHeight = 1
+			}
+			if refHeight > height {
+				refHeight = height
+			}
+			if yPos := height - refHeight; graph[yPos][x] == " " {
+				graph[yPos][x] = ReferenceStyle.Render("·")
+			}
+		}
+	}
+
+	// Markers go in the top row, over the bars
+	if len(opts.Markers) > 0 {
+		markStart := len(opts.Markers) - (len(values) - startIdx)
+		for x := 0; x < graphWidth && startIdx+x < len(values); x++ {
+			if mi := markStart + x; mi >= 0 && mi < len(opts.Markers) && opts.Markers[mi] {
+				graph[0][x] = MarkerStyle.Render("▼")
+			}
+		}
+	}
+
 	// Build the result with scale on the left
 	var result strings.Builder
 	
@@ -157,6 +294,42 @@ func CreateASCIIGraph(values []float64, width, height int) string {
 	return result.String()
 }
 
+// sparkLevels are the glyphs used for sparklines, lowest first.
+var sparkLevels = []string{
+	config.GraphBar1, config.GraphBar2, config.GraphBar3, config.GraphBar4,
+	config.GraphBar5, config.GraphBar6, config.GraphBar7, config.GraphBar8,
+}
+
+// CreateSparkline draws the most recent values as a one-line graph scaled
+// to max, colored with the usage gradient. Zero values are left blank.
+func CreateSparkline(values []float64, width int, max float64) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if max <= 0 {
+		max = 100
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		if v <= 0 {
+			b.WriteString(" ")
+			continue
+		}
+		level := v / max
+		if level > 1 {
+			level = 1
+		}
+		idx := int(level * float64(len(sparkLevels)-1))
+		b.WriteString(GetColorStyle(level * 100).Render(sparkLevels[idx]))
+	}
+	return b.String()
+}
+
 func CreateBox(title, content string, width, height int, borderColor lipgloss.Color) string {
 	style := lipgloss.NewStyle().
 		Foreground(borderColor).
@@ -183,6 +356,21 @@ func CreateSpinner(frame int) string {
 }
 
 func CreateCPUBar(label string, percentage float64, width int) string {
+	color := config.GetCPUColor(percentage)
+	return createCPUBar(label, percentage, width, func(barWidth int) string {
+		return CreateProgressBar(percentage, barWidth, color)
+	})
+}
+
+// CreateStackedCPUBar draws a CPU bar split into colored segments for each
+// CPU state, labelled with the busy percentage.
+func CreateStackedCPUBar(label string, breakdown metrics.CPUBreakdown, width int) string {
+	return createCPUBar(label, breakdown.Busy(), width, func(barWidth int) string {
+		return CreateSegmentedBar(CPUStateSegments(breakdown), barWidth)
+	})
+}
+
+func createCPUBar(label string, percentage float64, width int, fill func(barWidth int) string) string {
 	color := config.GetCPUColor(percentage)
 	percentStr := fmt.Sprintf("%5.1f%%", percentage)
 	
@@ -190,8 +378,8 @@ func CreateCPUBar(label string, percentage float64, width int) string {
 	compactLabel := label
 	if width < 50 {
 		// Shorten "Core X" to just the number for compact display
-		if strings.HasPrefix(label, "Core ") {
-			compactLabel = strings.TrimPrefix(label, "Core ")
+		if strings.HasPrefix(label, "Core ") || strings.HasPrefix(label, "CPU ") {
+			compactLabel = strings.TrimPrefix(strings.TrimPrefix(label, "Core "), "CPU ")
 			// Pad to 2 characters for alignment
 			if len(compactLabel) == 1 {
 				compactLabel = " " + compactLabel
@@ -225,12 +413,76 @@ func CreateCPUBar(label string, percentage float64, width int) string {
 		barWidth = 12
 	}
 	
-	bar := CreateProgressBar(percentage, barWidth, color)
+	bar := fill(barWidth)
 	
 	return labelStyle.Render(compactLabel) + " " + bar + " " + percentStyle.Render(percentStr)
 }
 
-func CreateMemoryBar(used, total uint64, percentage float64, width int) string {
+// CreateMemoryBar draws RAM as segments for used, buffers, shared and
+// cache memory, labelled with the used amount and percentage.
+func CreateMemoryBar(mem metrics.MemoryMetrics, percentage float64, width int) string {
+	return createUsageBar("Memory:", mem.Used, mem.Total, percentage, width, func(barWidth int) string {
+		return CreateSegmentedBar(MemorySegments(mem), barWidth)
+	})
+}
+
+// CreateUsageBar draws a single-color used/total bar in the memory layout.
+func CreateUsageBar(label string, used, total uint64, percentage float64, width int) string {
+	color := config.GetCPUColor(percentage)
+	return createUsageBar(label, used, total, percentage, width, func(barWidth int) string {
+		return CreateProgressBar(percentage, barWidth, color)
+	})
+}
+
+// CreateSwapBar draws swap usage in the same layout as the memory bar.
+func CreateSwapBar(used, total uint64, percentage float64, width int) string {
+	return CreateUsageBar("Swap:", used, total, percentage, width)
+}
+
+// MemorySegments splits RAM htop-style. gopsutil's Cached already includes
+// reclaimable slab and shared memory, so shared is carved out of it.
+func MemorySegments(mem metrics.MemoryMetrics) []BarSegment {
+	if mem.Total == 0 {
+		return nil
+	}
+	pct := func(v uint64) float64 {
+		return float64(v) / float64(mem.Total) * 100
+	}
+	cache := mem.Cached
+	if cache > mem.Shared {
+		cache -= mem.Shared
+	} else {
+		cache = 0
+	}
+	return []BarSegment{
+		{pct(mem.Used), MemUsedStyle},
+		{pct(mem.Buffers), MemBuffersStyle},
+		{pct(mem.Shared), MemSharedStyle},
+		{pct(cache), MemCacheStyle},
+	}
+}
+
+// CreateMemoryLegend lists the memory breakdown in the bar's colors,
+// followed by the figures that have no segment of their own.
+func CreateMemoryLegend(mem metrics.MemoryMetrics) string {
+	parts := []string{
+		MemUsedStyle.Render("used " + formatBytes(mem.Used)),
+		MemBuffersStyle.Render("buffers " + formatBytes(mem.Buffers)),
+		MemSharedStyle.Render("shared " + formatBytes(mem.Shared)),
+		MemCacheStyle.Render("cache " + formatBytes(mem.Cached)),
+		HelpStyle.Render("avail ") + formatBytes(mem.Available),
+		HelpStyle.Render("slab ") + formatBytes(mem.Slab),
+		HelpStyle.Render("dirty ") + formatBytes(mem.Dirty),
+		HelpStyle.Render("writeback ") + formatBytes(mem.Writeback),
+	}
+	if mem.HugePagesTotal > 0 {
+		parts = append(parts, HelpStyle.Render("hugepages ")+fmt.Sprintf("%d/%d free (%s)",
+			mem.HugePagesFree, mem.HugePagesTotal, formatBytes(mem.HugePageSize)))
+	}
+	return strings.Join(parts, "  ")
+}
+
+func createUsageBar(label string, used, total uint64, percentage float64, width int, fill func(barWidth int) string) string {
 	color := config.GetCPUColor(percentage)
 	usedStr := formatBytes(used)
 	totalStr := formatBytes(total)
@@ -258,10 +510,10 @@ func CreateMemoryBar(used, total uint64, percentage float64, width int) string {
 		barWidth = 22
 	}
 	
-	bar := CreateProgressBar(percentage, barWidth, color)
+	bar := fill(barWidth)
 	info := fmt.Sprintf("%s/%s", usedStr, totalStr)
 	
-	return labelStyle.Render("Memory:") + " " + bar + " " + infoStyle.Render(info) + " " + percentStyle.Render(percentStr)
+	return labelStyle.Render(label) + " " + bar + " " + infoStyle.Render(info) + " " + percentStyle.Render(percentStr)
 }
 
 func formatBytes(bytes uint64) string {
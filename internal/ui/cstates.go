@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/user/cpu-monitor/internal/metrics"
+)
+
+// idleMsg delivers a background read of the cpuidle counters.
+type idleMsg struct {
+	detail *metrics.IdleDetail
+}
+
+func idleCmd(collector *metrics.Collector) tea.Cmd {
+	return func() tea.Msg {
+		return idleMsg{detail: collector.CollectIdleDetail()}
+	}
+}
+
+func (m *Model) applyIdle(msg idleMsg) {
+	m.collectingIdle = false
+	m.idle = msg.detail
+}
+
+// idleStateStyle is the bar color of idle state k.
+func idleStateStyle(k int) lipgloss.Style {
+	return IdleStateStyles[k%len(IdleStateStyles)]
+}
+
+// idleSegments stacks active time first, then each idle state.
+func idleSegments(r metrics.IdleResidency) []BarSegment {
+	segments := []BarSegment{{r.Active, IdleActiveStyle}}
+	for k, v := range r.Residency {
+		segments = append(segments, BarSegment{v, idleStateStyle(k)})
+	}
+	return segments
+}
+
+// renderCStatesScreen shows the average residency of each C-state with its
+// exit latency and entry rate, then one stacked bar per CPU: active (C0)
+// time first, then idle states from shallow to deep.
+func (m Model) renderCStatesScreen() string {
+	if m.idle == nil {
+		return DimGrayStyle.Render("Reading cpuidle...")
+	}
+	states := m.idle.States
+	if len(states) == 0 {
+		return DimGrayStyle.Render("No cpuidle states found under <sysfs>/devices/system/cpu/cpuN/cpuidle (common in VMs)")
+	}
+
+	var b strings.Builder
+	orMissing := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	b.WriteString(HelpStyle.Render("Driver: ") + KeyStyle.Render(orMissing(m.idle.Driver)))
+	b.WriteString(HelpStyle.Render("  Governor: ") + KeyStyle.Render(orMissing(m.idle.Governor)))
+	if m.idle.Interval > 0 {
+		b.WriteString(HelpStyle.Render(fmt.Sprintf("  over the last %s", m.idle.Interval.Round(10*time.Millisecond))))
+	}
+	b.WriteString("\n\n")
+
+	if m.idle.Interval == 0 {
+		b.WriteString(DimGrayStyle.Render("Measuring residency until the next refresh..."))
+		return b.String()
+	}
+
+	// State table: average over all CPUs
+	avg := m.idle.Average
+	header := fmt.Sprintf("  %-8s %-24s %9s %9s %9s", "STATE", "DESCRIPTION", "LATENCY", "RESIDENCY", "ENTRIES/s")
+	b.WriteString(ProcessHeaderStyle.Render(padRight(header, m.width)))
+	b.WriteString("\n")
+	b.WriteString(IdleActiveStyle.Render("■ "))
+	b.WriteString(fmt.Sprintf("%-8s %-24s %9s %8.1f%% %9s\n", "C0", "active", "-", avg.Active, "-"))
+	for k, s := range states {
+		row := fmt.Sprintf("%-8s %-24s %9s %8.1f%% %9s",
+			truncateString(s.Name, 8),
+			truncateString(s.Desc, 24),
+			fmt.Sprintf("%dµs", s.Latency),
+			avg.Residency[k],
+			formatCount(avg.Entries[k]))
+		if s.Disabled {
+			row = DimGrayStyle.Render(row + "  disabled")
+		}
+		b.WriteString(idleStateStyle(k).Render("■ "))
+		b.WriteString(row)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	// Per-CPU bars with the share of each state on the right
+	// CPULabelStyle pads labels to 12 columns
+	const labelWidth = 12
+	numbersWidth := (len(states) + 1) * 7
+	barWidth := max(m.width-labelWidth-numbersWidth-1, 10)
+
+	columns := fmt.Sprintf("%-*s", labelWidth+barWidth+1, "CPU") + fmt.Sprintf("%6s ", "C0")
+	for _, s := range states {
+		columns += fmt.Sprintf("%6s ", truncateString(s.Name, 6))
+	}
+	b.WriteString(ProcessHeaderStyle.Render(padRight(columns, m.width)))
+	b.WriteString("\n")
+
+	// Header, system info, blank line, then the state table, the column
+	// header and the footer
+	rows := m.height - 4 - 2 - (len(states) + 3) - 1 - 2
+	cores := m.idle.Cores
+	shown := min(len(cores), max(rows, 1))
+	for _, core := range cores[:shown] {
+		b.WriteString(CPULabelStyle.Render(fmt.Sprintf("CPU %d", core.CPU)))
+		if core.Residency == nil {
+			b.WriteString(DimGrayStyle.Render("new since the last refresh"))
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(CreateSegmentedBar(idleSegments(core), barWidth))
+		b.WriteString(" ")
+		b.WriteString(IdleActiveStyle.Render(fmt.Sprintf("%6.1f ", core.Active)))
+		for k, v := range core.Residency {
+			b.WriteString(idleStateStyle(k).Render(fmt.Sprintf("%6.1f ", v)))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	footer := "% of time per state; deep states save power but add wake-up latency"
+	if shown < len(cores) {
+		footer += fmt.Sprintf("  (%d CPUs not shown, enlarge the terminal)", len(cores)-shown)
+	}
+	b.WriteString(HelpStyle.Render(footer))
+	return b.String()
+}
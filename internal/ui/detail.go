@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/user/cpu-monitor/internal/config"
+	"github.com/user/cpu-monitor/internal/metrics"
+)
+
+// detailMsg delivers a background process detail collection.
+type detailMsg struct {
+	pid    int32
+	detail *metrics.ProcessDetail
+	err    error
+}
+
+func detailCmd(collector *metrics.Collector, pid int32) tea.Cmd {
+	return func() tea.Msg {
+		detail, err := collector.CollectProcessDetail(pid)
+		return detailMsg{pid: pid, detail: detail, err: err}
+	}
+}
+
+// openDetail switches to the detail screen for the selected process.
+func (m *Model) openDetail() tea.Cmd {
+	if m.selectedPID == 0 {
+		return nil
+	}
+	m.detailPID = m.selectedPID
+	m.detail = nil
+	m.detailErr = nil
+	m.collectingDetail = true
+	return detailCmd(m.collector, m.detailPID)
+}
+
+func (m *Model) applyDetail(msg detailMsg) {
+	m.collectingDetail = false
+	// Ignore results for a process the user already navigated away from
+	if msg.pid != m.detailPID {
+		return
+	}
+	m.detail = msg.detail
+	m.detailErr = msg.err
+}
+
+func (m Model) renderDetailScreen() string {
+	var b strings.Builder
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(config.Colors.NeonBlue).
+		Width(14)
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(config.Colors.NeonPurple).
+		Bold(true)
+
+	row := func(label, value string) {
+		b.WriteString(labelStyle.Render(label))
+		b.WriteString(truncateString(value, m.width-14))
+		b.WriteString("\n")
+	}
+
+	if m.detailErr != nil {
+		b.WriteString(RedStyle.Render(fmt.Sprintf("Process %d: %v", m.detailPID, m.detailErr)))
+		b.WriteString("\n\n")
+		b.WriteString(HelpStyle.Render("esc: back"))
+		return b.String()
+	}
+	if m.detail == nil {
+		return DimGrayStyle.Render(fmt.Sprintf("Loading process %d...", m.detailPID))
+	}
+
+	d := m.detail
+	row("PID", fmt.Sprintf("%d (%s)", d.PID, d.Name))
+	row("Command", strings.Join(d.Cmdline, " "))
+	row("Cwd", orUnavailable(d.Cwd))
+	if d.EnvVars > 0 {
+		row("Environment", fmt.Sprintf("%d variables, %s", d.EnvVars, formatBytes(uint64(d.EnvBytes))))
+	} else {
+		row("Environment", orUnavailable(""))
+	}
+	row("Open FDs", fmt.Sprintf("%d", d.NumFDs))
+	row("Cgroup", orUnavailable(d.Cgroup))
+	row("Affinity", orUnavailable(d.Affinity))
+
+	// CPU history sparkline from the per-PID history
+	b.WriteString("\n")
+	b.WriteString(sectionStyle.Render("CPU History"))
+	b.WriteString("\n")
+	if hist, ok := m.processHistories[d.PID]; ok {
+		values := hist.GetValues()
+		peak := 100.0
+		for _, v := range values {
+			if v > peak {
+				peak = v
+			}
+		}
+		last := 0.0
+		if len(values) > 0 {
+			last = values[len(values)-1]
+		}
+		sparkWidth := m.width - 24
+		b.WriteString(CreateSparkline(values, sparkWidth, peak))
+		b.WriteString(HelpStyle.Render(fmt.Sprintf("  now %5.1f%% max %3.0f%%", last, peak)))
+	}
+	b.WriteString("\n\n")
+
+	// Thread list, busiest first, limited to the remaining height
+	b.WriteString(sectionStyle.Render(fmt.Sprintf("Threads (%d)", len(d.Threads))))
+	b.WriteString("\n")
+	b.WriteString(ProcessHeaderStyle.Render(padRight(fmt.Sprintf("%7s %6s %10s %s", "TID", "CPU%", "TIME", "NAME"), m.width)))
+	b.WriteString("\n")
+
+	maxThreads := m.height - 21
+	if maxThreads < 1 {
+		maxThreads = 1
+	}
+	for i, t := range d.Threads {
+		if i >= maxThreads {
+			b.WriteString(HelpStyle.Render(fmt.Sprintf("  ... %d more", len(d.Threads)-i)))
+			b.WriteString("\n")
+			break
+		}
+		cpuStyle := lipgloss.NewStyle().Foreground(config.GetCPUColor(t.CPUPercent))
+		b.WriteString(fmt.Sprintf("%7d ", t.TID))
+		b.WriteString(cpuStyle.Render(fmt.Sprintf("%6.1f", t.CPUPercent)))
+		b.WriteString(fmt.Sprintf(" %10s %s\n", formatCPUTime(t.CPUTime), t.Name))
+	}
+
+	b.WriteString(HelpStyle.Render("esc: back"))
+	return b.String()
+}
+
+func orUnavailable(s string) string {
+	if s == "" {
+		return DimGrayStyle.Render("unavailable")
+	}
+	return s
+}
+
+// formatCPUTime renders CPU seconds like ps TIME (m:ss.cc).
+func formatCPUTime(seconds float64) string {
+	minutes := int(seconds) / 60
+	return fmt.Sprintf("%d:%05.2f", minutes, seconds-float64(minutes*60))
+}
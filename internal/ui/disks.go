@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/cpu-monitor/internal/metrics"
+)
+
+// diskHistory keeps the sparkline data of one block device.
+type diskHistory struct {
+	read  *metrics.History
+	write *metrics.History
+	util  *metrics.History
+}
+
+// updateDiskHistories appends each device's rates and forgets devices
+// that have disappeared.
+func (m *Model) updateDiskHistories() {
+	seen := make(map[string]bool, len(m.metrics.Disks))
+	for _, d := range m.metrics.Disks {
+		seen[d.Name] = true
+		hist, ok := m.diskHistories[d.Name]
+		if !ok {
+			hist = &diskHistory{
+				read:  metrics.NewHistory(m.config.HistorySize, m.config.MovingAvgSize),
+				write: metrics.NewHistory(m.config.HistorySize, m.config.MovingAvgSize),
+				util:  metrics.NewHistory(m.config.HistorySize, m.config.MovingAvgSize),
+			}
+			m.diskHistories[d.Name] = hist
+		}
+		hist.read.Add(d.ReadRate)
+		hist.write.Add(d.WriteRate)
+		hist.util.Add(d.Utilization)
+	}
+	for name := range m.diskHistories {
+		if !seen[name] {
+			delete(m.diskHistories, name)
+		}
+	}
+}
+
+// historyPeak returns the largest value, or floor if all are smaller, so
+// rate sparklines scale to their own history.
+func historyPeak(values []float64, floor float64) float64 {
+	peak := floor
+	for _, v := range values {
+		if v > peak {
+			peak = v
+		}
+	}
+	return peak
+}
+
+// renderDisksScreen shows throughput, IOPS, latency and utilization of
+// every disk, each with read/write/util sparklines.
+func (m Model) renderDisksScreen() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s %s  %s\n\n",
+		HelpStyle.Render("CPU iowait:"),
+		IowaitStateStyle.Render(fmt.Sprintf("%.1f%%", m.metrics.TotalBreakdown.Iowait)),
+		HelpStyle.Render("time CPUs sat idle waiting on the disks below")))
+
+	if len(m.metrics.Disks) == 0 {
+		b.WriteString(DimGrayStyle.Render("No block device activity found in <procfs>/diskstats"))
+		return b.String()
+	}
+
+	// Each sparkline gets a third of the row after its label
+	sparkWidth := (m.width-8)/3 - 8
+	if sparkWidth < 8 {
+		sparkWidth = 8
+	}
+
+	for _, d := range m.metrics.Disks {
+		b.WriteString(KeyStyle.Render(fmt.Sprintf("%-8s", truncateString(d.Name, 8))))
+		b.WriteString(fmt.Sprintf("%s %10s %7.0f IOPS %6.1f ms   %s %10s %7.0f IOPS %6.1f ms   %s %s",
+			HelpStyle.Render("read"), formatBytes(uint64(d.ReadRate))+"/s", d.ReadIOPS, d.ReadLatency,
+			HelpStyle.Render("write"), formatBytes(uint64(d.WriteRate))+"/s", d.WriteIOPS, d.WriteLatency,
+			HelpStyle.Render("util"), GetColorStyle(d.Utilization).Render(fmt.Sprintf("%5.1f%%", d.Utilization)),
+		))
+		b.WriteString("\n")
+
+		if hist, ok := m.diskHistories[d.Name]; ok {
+			reads, writes := hist.read.GetValues(), hist.write.GetValues()
+			// Share one scale so read and write heights are comparable
+			peak := historyPeak(writes, historyPeak(reads, 1))
+			b.WriteString(strings.Repeat(" ", 8))
+			b.WriteString(HelpStyle.Render("read  ") + CreateSparkline(reads, sparkWidth, peak) + "  ")
+			b.WriteString(HelpStyle.Render("write ") + CreateSparkline(writes, sparkWidth, peak) + "  ")
+			b.WriteString(HelpStyle.Render("util  ") + CreateSparkline(hist.util.GetValues(), sparkWidth, 100))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(HelpStyle.Render("latency: average ms per completed request incl. queueing  util: share of time with I/O in flight"))
+
+	return b.String()
+}
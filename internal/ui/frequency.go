@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/user/cpu-monitor/internal/config"
+	"github.com/user/cpu-monitor/internal/metrics"
+)
+
+// frequencyMsg delivers a background read of the cpufreq policies.
+type frequencyMsg struct {
+	detail *metrics.FrequencyDetail
+}
+
+func frequencyCmd(collector *metrics.Collector) tea.Cmd {
+	return func() tea.Msg {
+		return frequencyMsg{detail: collector.CollectFrequencyDetail()}
+	}
+}
+
+func (m *Model) applyFrequency(msg frequencyMsg) {
+	m.collectingFrequency = false
+	m.frequency = msg.detail
+}
+
+// policyGroup is a set of CPUs sharing the same cpufreq settings.
+type policyGroup struct {
+	policy metrics.CPUFreqPolicy
+	cpus   []int
+	minCur float64
+	maxCur float64
+}
+
+// groupPolicies merges CPUs whose driver, governor, EPP and limits are
+// identical, keeping the range of their current frequencies.
+func groupPolicies(policies []metrics.CPUFreqPolicy) []policyGroup {
+	var groups []policyGroup
+	for _, p := range policies {
+		key := p
+		key.CPU, key.Current = 0, 0
+		found := false
+		for i := range groups {
+			g := &groups[i]
+			if g.policy == key {
+				g.cpus = append(g.cpus, p.CPU)
+				g.minCur = min(g.minCur, p.Current)
+				g.maxCur = max(g.maxCur, p.Current)
+				found = true
+				break
+			}
+		}
+		if !found {
+			groups = append(groups, policyGroup{policy: key, cpus: []int{p.CPU}, minCur: p.Current, maxCur: p.Current})
+		}
+	}
+	return groups
+}
+
+// formatCPUList renders sorted CPU numbers in the kernel's list format.
+func formatCPUList(cpus []int) string {
+	var parts []string
+	for i := 0; i < len(cpus); {
+		j := i
+		for j+1 < len(cpus) && cpus[j+1] == cpus[j]+1 {
+			j++
+		}
+		if j > i {
+			parts = append(parts, fmt.Sprintf("%d-%d", cpus[i], cpus[j]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d", cpus[i]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// Column widths of the table; rows style each column separately
+const frequencyRowFormat = "%-12s %-14s %-12s %-22s %-13s %-13s %s"
+
+// renderFrequencyScreen lists cpufreq settings per group of CPUs and a
+// histogram of time spent at each frequency. Governors other than
+// performance/schedutil and scaling limits below the hardware range are
+// highlighted, since they usually explain a CPU that won't clock up.
+func (m Model) renderFrequencyScreen() string {
+	if m.frequency == nil {
+		return DimGrayStyle.Render("Reading cpufreq...")
+	}
+	if len(m.frequency.Policies) == 0 {
+		return DimGrayStyle.Render("No cpufreq support found under <sysfs>/devices/system/cpu (common in VMs and containers)")
+	}
+
+	var b strings.Builder
+	header := fmt.Sprintf(frequencyRowFormat, "CPUS", "DRIVER", "GOVERNOR", "EPP", "CURRENT", "SCALING", "HARDWARE")
+	b.WriteString(ProcessHeaderStyle.Render(padRight(header, m.width)))
+	b.WriteString("\n")
+
+	orMissing := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	mhzRange := func(lo, hi float64) string {
+		if lo == hi {
+			return formatFrequency(lo)
+		}
+		return formatFrequency(lo) + "-" + formatFrequency(hi)
+	}
+
+	for _, g := range groupPolicies(m.frequency.Policies) {
+		p := g.policy
+		governorStyle := KeyStyle
+		if p.Governor == "powersave" || p.Governor == "conservative" || p.Governor == "userspace" {
+			governorStyle = OrangeStyle
+		}
+		eppStyle := HelpStyle
+		if strings.Contains(p.EPP, "power") {
+			eppStyle = OrangeStyle
+		}
+		scalingStyle := HelpStyle
+		if (p.HardwareMax > 0 && p.ScalingMax < p.HardwareMax) || p.ScalingMin > p.HardwareMin {
+			scalingStyle = OrangeStyle
+		}
+
+		b.WriteString(fmt.Sprintf("%-12s %-14s %s %s %s %s %s",
+			truncateString(formatCPUList(g.cpus), 12),
+			truncateString(orMissing(p.Driver), 14),
+			governorStyle.Render(fmt.Sprintf("%-12s", truncateString(orMissing(p.Governor), 12))),
+			eppStyle.Render(fmt.Sprintf("%-22s", truncateString(orMissing(p.EPP), 22))),
+			FreqStyle.Render(fmt.Sprintf("%-13s", mhzRange(g.minCur, g.maxCur))),
+			scalingStyle.Render(fmt.Sprintf("%-13s", mhzRange(p.ScalingMin, p.ScalingMax))),
+			HelpStyle.Render(mhzRange(p.HardwareMin, p.HardwareMax)),
+		))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	title := "Time in state (since last refresh, all CPUs)"
+	if m.frequency.TimeInStateSinceBoot {
+		title = "Time in state (since boot, all CPUs)"
+	}
+	b.WriteString(ProcessHeaderStyle.Render(title))
+	b.WriteString("\n")
+	if len(m.frequency.TimeInState) == 0 {
+		b.WriteString(DimGrayStyle.Render("No cpufreq stats (time_in_state) available for this driver"))
+		b.WriteString("\n")
+	}
+	barWidth := m.width - 20
+	for _, r := range m.frequency.TimeInState {
+		b.WriteString(FreqStyle.Render(fmt.Sprintf("%10s ", formatFrequency(r.MHz)+"Hz")))
+		b.WriteString(CreateProgressBar(r.Share, barWidth, config.GetCPUColor(r.Share)))
+		b.WriteString(fmt.Sprintf(" %5.1f%%\n", r.Share))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(HelpStyle.Render("orange: power-saving governor or EPP, or scaling limits narrower than the hardware range"))
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/user/cpu-monitor/internal/metrics"
+)
+
+// irqLabelWidth is the width of the row label in the heatmap.
+const irqLabelWidth = 24
+
+// renderIRQScreen draws the busiest interrupt sources as rows of a heatmap
+// with one column per CPU. Cells are colored on a log scale against the
+// busiest cell, so one hot column stands out even next to quieter rows.
+func (m Model) renderIRQScreen() string {
+	cpus := m.metrics.InterruptCPUs
+	if len(cpus) == 0 || len(m.metrics.Interrupts) == 0 {
+		return DimGrayStyle.Render("No interrupt counters found in <procfs>/interrupts")
+	}
+
+	// Two columns per CPU when they fit, otherwise one, then truncate
+	prefixWidth := irqLabelWidth + 10
+	cellWidth := 2
+	if prefixWidth+len(cpus)*cellWidth > m.width {
+		cellWidth = 1
+	}
+	shownCPUs := len(cpus)
+	if maxCPUs := (m.width - prefixWidth) / cellWidth; shownCPUs > maxCPUs {
+		shownCPUs = max(maxCPUs, 1)
+	}
+
+	// Header, system info, blank line, column header and two footer lines
+	rows := m.height - 7
+	var busy []metrics.IRQRate
+	for _, irq := range m.metrics.Interrupts {
+		if irq.Total <= 0 || len(busy) >= rows {
+			break
+		}
+		busy = append(busy, irq)
+	}
+
+	peak := 0.0
+	for _, irq := range busy {
+		for _, v := range irq.PerCPU[:min(shownCPUs, len(irq.PerCPU))] {
+			peak = math.Max(peak, v)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(ProcessHeaderStyle.Render(padRight(m.irqColumnHeader(cpus[:shownCPUs], prefixWidth, cellWidth), m.width)))
+	b.WriteString("\n")
+
+	if len(busy) == 0 {
+		b.WriteString(DimGrayStyle.Render("No interrupts since the last refresh"))
+		b.WriteString("\n")
+	}
+
+	for _, irq := range busy {
+		name := irq.Name
+		if irq.Softirq {
+			name = "soft " + name
+		} else if irq.Label != "" {
+			name += " " + irq.Label
+		}
+		b.WriteString(KeyStyle.Render(fmt.Sprintf("%-*s", irqLabelWidth, truncateString(name, irqLabelWidth))))
+		b.WriteString(fmt.Sprintf(" %8s ", formatCount(irq.Total)))
+
+		for i := 0; i < shownCPUs && i < len(irq.PerCPU); i++ {
+			v := irq.PerCPU[i]
+			if v <= 0 {
+				b.WriteString(DimGrayStyle.Render(strings.Repeat("·", cellWidth)))
+				continue
+			}
+			level := math.Log1p(v) / math.Log1p(peak) * 100
+			b.WriteString(GetColorStyle(level).Render(strings.Repeat("█", cellWidth)))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	footer := fmt.Sprintf("%d of %d sources  rate/s  colors: log scale up to %s/s per CPU",
+		len(busy), len(m.metrics.Interrupts), formatCount(peak))
+	if shownCPUs < len(cpus) {
+		footer += fmt.Sprintf("  (%d CPUs not shown, widen the terminal)", len(cpus)-shownCPUs)
+	}
+	b.WriteString(HelpStyle.Render(footer))
+
+	return b.String()
+}
+
+// irqColumnHeader labels every fourth CPU column with its number.
+func (m Model) irqColumnHeader(cpus []int, prefixWidth, cellWidth int) string {
+	header := []rune(fmt.Sprintf("%-*s %8s ", irqLabelWidth, "SOURCE", "TOTAL/s") +
+		strings.Repeat(" ", len(cpus)*cellWidth))
+	for i, cpu := range cpus {
+		if i%4 != 0 {
+			continue
+		}
+		label := []rune(fmt.Sprintf("%d", cpu))
+		pos := prefixWidth + i*cellWidth
+		if pos+len(label) > len(header) {
+			break
+		}
+		copy(header[pos:], label)
+	}
+	return string(header)
+}
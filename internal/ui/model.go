@@ -8,16 +8,101 @@ import (
 	"github.com/user/cpu-monitor/internal/metrics"
 )
 
+// screen identifies the full-screen view selected with Tab.
+type screen int
+
+const (
+	screenDashboard screen = iota
+	screenSensors
+	screenProcesses
+	screenTree
+	screenPressure
+	screenCgroups
+	screenDisks
+	screenNetwork
+	screenIRQs
+	screenFrequency
+	screenCStates
+	screenCount
+)
+
+var screenNames = [screenCount]string{
+	screenDashboard: "Dashboard",
+	screenSensors:   "Sensors",
+	screenProcesses: "Processes",
+	screenTree:      "Tree",
+	screenPressure:  "Pressure",
+	screenCgroups:   "Cgroups",
+	screenDisks:     "Disks",
+	screenNetwork:   "Network",
+	screenIRQs:      "IRQs",
+	screenFrequency: "Frequency",
+	screenCStates:   "C-states",
+}
+
 type Model struct {
 	metrics       *metrics.CPUMetrics
 	collector     *metrics.Collector
 	history       *metrics.History
-	coreHistories []*metrics.History
+	// systemHistory holds whole-system usage as a reference line while
+	// history tracks the scoped processes
+	systemHistory *metrics.History
+	// throttleHistory holds thermal throttle events per sample of history
+	throttleHistory *metrics.History
+	coreHistories map[int]*metrics.History
 	config        config.Config
 	width         int
 	height        int
 	paused        bool
+	collecting    bool
 	showHelp      bool
+	screen        screen
+	// groupByTopology groups the dashboard's CPU bars by socket, NUMA
+	// node and physical core
+	groupByTopology bool
+	// Process table state; the selection follows a PID across re-sorts
+	processSort        processSortColumn
+	processSortReverse bool
+	selectedPID        int32
+	// PIDs whose children are hidden in the tree view
+	collapsed map[int32]bool
+	// Signal/renice dialog and the result of the last action
+	action     processAction
+	statusLine string
+	statusErr  bool
+	// Per-PID CPU history, appended each time the process list refreshes
+	processHistories     map[int32]*metrics.History
+	lastProcessTimestamp time.Time
+	// Process detail screen; detailPID is 0 while it is closed
+	detailPID        int32
+	detail           *metrics.ProcessDetail
+	detailErr        error
+	collectingDetail bool
+	// PSI "some" and "full" avg10 history per resource
+	pressureHistories [pressureResourceCount]pressureHistory
+	// Scheduler rates and task counts from /proc/stat
+	schedHistory schedHistory
+	// Per-device I/O history, keyed by device name
+	diskHistories map[string]*diskHistory
+	// Per-interface traffic history and the interface kinds shown
+	netHistories map[string]*netHistory
+	netFilter    netFilter
+	// Cgroup ranking, walked only while the cgroups screen is open
+	cgroupCollector   *metrics.CgroupCollector
+	cgroups           []metrics.CgroupUsage
+	cgroupsErr        error
+	collectingCgroups bool
+	// cpufreq policies and residency, read only while the frequency
+	// screen is open
+	frequency           *metrics.FrequencyDetail
+	collectingFrequency bool
+	// C-state residency, read only while the C-states screen is open
+	idle           *metrics.IdleDetail
+	collectingIdle bool
+	// onMetrics, when set, sees every collection the UI applies
+	onMetrics func(*metrics.CPUMetrics)
+	// interrupted is set when the view was closed with Ctrl+C rather than q
+	interrupted bool
 	spinnerFrame  int
 	lastUpdate    time.Time
 	startTime     time.Time
@@ -25,34 +110,56 @@ type Model struct {
 }
 
 func NewModel(cfg config.Config) Model {
-	collector := metrics.NewCollector()
-	initialMetrics, _ := collector.Collect()
-	
-	var coreHistories []*metrics.History
-	if initialMetrics != nil {
-		for range initialMetrics.PerCoreUsage {
-			coreHistories = append(coreHistories, 
-				metrics.NewHistory(cfg.HistorySize, cfg.MovingAvgSize))
-		}
+	return NewModelWithCollector(cfg, metrics.NewCollector(CollectorOptions(cfg)))
+}
+
+// CollectorOptions maps the configuration onto collector options.
+func CollectorOptions(cfg config.Config) metrics.Options {
+	return metrics.Options{
+		SysfsRoot:  cfg.SysfsRoot,
+		ProcfsRoot: cfg.ProcfsRoot,
+		CgroupRoot: cfg.CgroupRoot,
+		TempSensor: cfg.TempSensor,
+		Scope: metrics.Scope{
+			PIDs: cfg.ScopePIDs,
+			Name: cfg.ScopeName,
+		},
 	}
-	
+}
+
+// NewModelWithCollector builds a model around an existing collector, so
+// callers can share it with their own sampling once the UI has exited.
+func NewModelWithCollector(cfg config.Config, collector *metrics.Collector) Model {
+	// The first collection is started by Init, so the first frame renders
+	// immediately and shows "Initializing..." until metrics arrive.
 	return Model{
-		metrics:       initialMetrics,
 		collector:     collector,
 		history:       metrics.NewHistory(cfg.HistorySize, cfg.MovingAvgSize),
-		coreHistories: coreHistories,
+		systemHistory: metrics.NewHistory(cfg.HistorySize, cfg.MovingAvgSize),
+		throttleHistory: metrics.NewHistory(cfg.HistorySize, cfg.MovingAvgSize),
 		config:        cfg,
 		width:         80,
 		height:        24,
 		paused:        false,
+		collecting:    true,
+		collapsed:     make(map[int32]bool),
+		coreHistories: make(map[int]*metrics.History),
 		spinnerFrame:  0,
 		startTime:     time.Now(),
 		lastUpdate:    time.Now(),
+
+		processHistories:  make(map[int32]*metrics.History),
+		pressureHistories: newPressureHistories(cfg),
+		cgroupCollector:   metrics.NewCgroupCollector(cfg.CgroupRoot),
+		schedHistory:      newSchedHistory(cfg),
+		diskHistories:     make(map[string]*diskHistory),
+		netHistories:      make(map[string]*netHistory),
 	}
 }
 
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
+		collectCmd(m.collector),
 		tickCmd(m.config.RefreshRate),
 		tea.WindowSize(),
 	)
@@ -66,38 +173,127 @@ func tickCmd(duration time.Duration) tea.Cmd {
 	})
 }
 
-func (m *Model) collectMetrics() {
-	newMetrics, err := m.collector.Collect()
-	if err != nil {
-		m.err = err
+// metricsMsg delivers the result of a background collection.
+type metricsMsg struct {
+	metrics *metrics.CPUMetrics
+	err     error
+}
+
+// collectCmd runs a collection off the Bubble Tea update loop so slow
+// metric sources never stall key handling or rendering.
+func collectCmd(collector *metrics.Collector) tea.Cmd {
+	return func() tea.Msg {
+		newMetrics, err := collector.Collect()
+		return metricsMsg{metrics: newMetrics, err: err}
+	}
+}
+
+func (m *Model) applyMetrics(msg metricsMsg) {
+	if msg.err != nil {
+		m.err = msg.err
 		return
 	}
 	
-	m.metrics = newMetrics
+	m.metrics = msg.metrics
 	m.err = nil
 	
-	if m.metrics.TotalUsage > 0 {
+	throttled := float64(m.metrics.Throttle.Events())
+	if m.metrics.Scope != nil {
+		m.history.Add(m.metrics.Scope.Usage)
+		m.systemHistory.Add(m.metrics.TotalUsage)
+		m.throttleHistory.Add(throttled)
+	} else if m.metrics.TotalUsage > 0 {
 		m.history.Add(m.metrics.TotalUsage)
+		m.throttleHistory.Add(throttled)
 	}
 	
+	// Histories follow the kernel CPU number, so hot-added CPUs get a new
+	// one and offline CPUs keep theirs until they come back
 	for i, usage := range m.metrics.PerCoreUsage {
-		if i < len(m.coreHistories) {
-			m.coreHistories[i].Add(usage)
+		id := i
+		if i < len(m.metrics.CPUIDs) {
+			id = m.metrics.CPUIDs[i]
 		}
+		hist, ok := m.coreHistories[id]
+		if !ok {
+			hist = metrics.NewHistory(m.config.HistorySize, m.config.MovingAvgSize)
+			m.coreHistories[id] = hist
+		}
+		hist.Add(usage)
 	}
-	
-	if len(m.coreHistories) < len(m.metrics.PerCoreUsage) {
-		for i := len(m.coreHistories); i < len(m.metrics.PerCoreUsage); i++ {
-			hist := metrics.NewHistory(m.config.HistorySize, m.config.MovingAvgSize)
-			hist.Add(m.metrics.PerCoreUsage[i])
-			m.coreHistories = append(m.coreHistories, hist)
+
+	m.updateProcessHistories()
+	m.syncSelection()
+	m.updatePressureHistories()
+	m.schedHistory.add(m.metrics)
+	m.updateDiskHistories()
+	m.updateNetHistories()
+
+	if m.onMetrics != nil {
+		m.onMetrics(m.metrics)
+	}
+}
+
+// WithMetricsHook returns a copy of the model that passes every applied
+// collection to fn, e.g. to record a summary of a wrapped command.
+func (m Model) WithMetricsHook(fn func(*metrics.CPUMetrics)) Model {
+	m.onMetrics = fn
+	return m
+}
+
+// Interrupted reports whether the user quit with Ctrl+C, which the
+// terminal can't deliver as SIGINT while the view holds it in raw mode.
+func (m Model) Interrupted() bool {
+	return m.interrupted
+}
+
+// updateProcessHistories records each process's CPU usage once per process
+// list refresh and forgets processes that have exited.
+func (m *Model) updateProcessHistories() {
+	if !m.metrics.ProcessTimestamp.After(m.lastProcessTimestamp) {
+		return
+	}
+	m.lastProcessTimestamp = m.metrics.ProcessTimestamp
+
+	seen := make(map[int32]bool, len(m.metrics.Processes))
+	for _, p := range m.metrics.Processes {
+		seen[p.PID] = true
+		hist, ok := m.processHistories[p.PID]
+		if !ok {
+			hist = metrics.NewHistory(m.config.HistorySize, m.config.MovingAvgSize)
+			m.processHistories[p.PID] = hist
+		}
+		hist.Add(p.CPUPercent)
+	}
+	for pid := range m.processHistories {
+		if !seen[pid] {
+			delete(m.processHistories, pid)
 		}
 	}
 }
 
 func (m *Model) resetHistory() {
 	m.history.Reset()
+	m.systemHistory.Reset()
+	m.throttleHistory.Reset()
 	for _, h := range m.coreHistories {
 		h.Reset()
 	}
+	for _, h := range m.processHistories {
+		h.Reset()
+	}
+	for _, h := range m.pressureHistories {
+		h.some.Reset()
+		h.full.Reset()
+	}
+	m.schedHistory.reset()
+	for _, h := range m.diskHistories {
+		h.read.Reset()
+		h.write.Reset()
+		h.util.Reset()
+	}
+	for _, h := range m.netHistories {
+		h.rx.Reset()
+		h.tx.Reset()
+	}
 }
\ No newline at end of file
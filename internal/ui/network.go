@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/user/cpu-monitor/internal/metrics"
+)
+
+// netHistory keeps the sparkline data of one interface.
+type netHistory struct {
+	rx *metrics.History
+	tx *metrics.History
+}
+
+// netFilter hides interface kinds that are usually noise on container
+// hosts. Loopback and veth pairs are hidden by default.
+type netFilter struct {
+	showLoopback bool
+	showVeth     bool
+	hideBridges  bool
+}
+
+func (f netFilter) visible(n metrics.NetStats) bool {
+	switch {
+	case n.Loopback:
+		return f.showLoopback
+	case n.Veth:
+		return f.showVeth
+	case n.Bridge:
+		return !f.hideBridges
+	}
+	return true
+}
+
+// updateNetHistories appends each interface's rates and forgets
+// interfaces that have disappeared.
+func (m *Model) updateNetHistories() {
+	seen := make(map[string]bool, len(m.metrics.Network))
+	for _, n := range m.metrics.Network {
+		seen[n.Name] = true
+		hist, ok := m.netHistories[n.Name]
+		if !ok {
+			hist = &netHistory{
+				rx: metrics.NewHistory(m.config.HistorySize, m.config.MovingAvgSize),
+				tx: metrics.NewHistory(m.config.HistorySize, m.config.MovingAvgSize),
+			}
+			m.netHistories[n.Name] = hist
+		}
+		hist.rx.Add(n.RxRate)
+		hist.tx.Add(n.TxRate)
+	}
+	for name := range m.netHistories {
+		if !seen[name] {
+			delete(m.netHistories, name)
+		}
+	}
+}
+
+// handleNetworkKey toggles the interface filters. It reports whether the
+// key was consumed.
+func (m *Model) handleNetworkKey(key string) bool {
+	switch key {
+	case "l":
+		m.netFilter.showLoopback = !m.netFilter.showLoopback
+	case "v":
+		m.netFilter.showVeth = !m.netFilter.showVeth
+	case "b":
+		m.netFilter.hideBridges = !m.netFilter.hideBridges
+	default:
+		return false
+	}
+	return true
+}
+
+// renderNetworkScreen shows traffic, packet, error and drop rates of each
+// visible interface with rx/tx sparklines.
+func (m Model) renderNetworkScreen() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s %s  %s\n\n",
+		HelpStyle.Render("CPU softirq:"),
+		SoftirqStateStyle.Render(fmt.Sprintf("%.1f%%", m.metrics.TotalBreakdown.Softirq)),
+		HelpStyle.Render("time spent in deferred interrupt work, mostly network receive")))
+
+	hidden := 0
+	shown := 0
+	sparkWidth := (m.width-8)/2 - 10
+	if sparkWidth < 8 {
+		sparkWidth = 8
+	}
+
+	for _, n := range m.metrics.Network {
+		if !m.netFilter.visible(n) {
+			hidden++
+			continue
+		}
+		shown++
+
+		problemStyle := func(v float64) lipgloss.Style {
+			if v > 0 {
+				return RedStyle
+			}
+			return DimGrayStyle
+		}
+		b.WriteString(KeyStyle.Render(fmt.Sprintf("%-8s", truncateString(n.Name, 8))))
+		b.WriteString(fmt.Sprintf("%s %10s %8.0f pkt/s   %s %10s %8.0f pkt/s   %s %s  %s %s",
+			HelpStyle.Render("rx"), formatBytes(uint64(n.RxRate))+"/s", n.RxPackets,
+			HelpStyle.Render("tx"), formatBytes(uint64(n.TxRate))+"/s", n.TxPackets,
+			HelpStyle.Render("err/s"), problemStyle(n.RxErrors+n.TxErrors).Render(fmt.Sprintf("%.0f/%.0f", n.RxErrors, n.TxErrors)),
+			HelpStyle.Render("drop/s"), problemStyle(n.RxDrops+n.TxDrops).Render(fmt.Sprintf("%.0f/%.0f", n.RxDrops, n.TxDrops)),
+		))
+		b.WriteString("\n")
+
+		if hist, ok := m.netHistories[n.Name]; ok {
+			rx, tx := hist.rx.GetValues(), hist.tx.GetValues()
+			peak := historyPeak(tx, historyPeak(rx, 1))
+			b.WriteString(strings.Repeat(" ", 8))
+			b.WriteString(HelpStyle.Render("rx ") + CreateSparkline(rx, sparkWidth, peak) + "   ")
+			b.WriteString(HelpStyle.Render("tx ") + CreateSparkline(tx, sparkWidth, peak))
+			b.WriteString("\n")
+		}
+	}
+	if shown == 0 {
+		b.WriteString(DimGrayStyle.Render("No interfaces to show"))
+		b.WriteString("\n")
+	}
+
+	onOff := func(on bool) string {
+		if on {
+			return "shown"
+		}
+		return "hidden"
+	}
+	b.WriteString("\n")
+	b.WriteString(HelpStyle.Render(fmt.Sprintf(
+		"%d hidden  l: loopback %s  v: veth %s  b: bridges %s  err/drop as rx/tx",
+		hidden, onOff(m.netFilter.showLoopback), onOff(m.netFilter.showVeth), onOff(!m.netFilter.hideBridges))))
+
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/user/cpu-monitor/internal/config"
+	"github.com/user/cpu-monitor/internal/metrics"
+)
+
+// pressureResource indexes the PSI resources in display order.
+type pressureResource int
+
+const (
+	pressureCPU pressureResource = iota
+	pressureMemory
+	pressureIO
+	pressureResourceCount
+)
+
+var pressureNames = [pressureResourceCount]string{
+	pressureCPU:    "CPU",
+	pressureMemory: "Memory",
+	pressureIO:     "IO",
+}
+
+type pressureHistory struct {
+	some *metrics.History
+	full *metrics.History
+}
+
+func newPressureHistories(cfg config.Config) [pressureResourceCount]pressureHistory {
+	var h [pressureResourceCount]pressureHistory
+	for i := range h {
+		h[i] = pressureHistory{
+			some: metrics.NewHistory(cfg.HistorySize, cfg.MovingAvgSize),
+			full: metrics.NewHistory(cfg.HistorySize, cfg.MovingAvgSize),
+		}
+	}
+	return h
+}
+
+func pressureOf(pm metrics.PressureMetrics, r pressureResource) metrics.Pressure {
+	switch r {
+	case pressureMemory:
+		return pm.Memory
+	case pressureIO:
+		return pm.IO
+	}
+	return pm.CPU
+}
+
+func (m *Model) updatePressureHistories() {
+	if !m.metrics.Pressure.Available {
+		return
+	}
+	for r := pressureResource(0); r < pressureResourceCount; r++ {
+		p := pressureOf(m.metrics.Pressure, r)
+		m.pressureHistories[r].some.Add(p.Some.Avg10)
+		m.pressureHistories[r].full.Add(p.Full.Avg10)
+	}
+}
+
+// renderPressureScreen shows PSI for each resource: the current averages
+// and a graph of "some" avg10 with "full" avg10 as a dotted line.
+func (m Model) renderPressureScreen() string {
+	if !m.metrics.Pressure.Available {
+		return DimGrayStyle.Render("Pressure stall information is not available (needs a kernel with CONFIG_PSI, read from <procfs>/pressure)")
+	}
+
+	// Header, system info and the blank line above take 4 rows; each
+	// resource needs a stats line plus the graph's border and axis rows
+	graphHeight := (m.height-4-1)/int(pressureResourceCount) - 5
+	if graphHeight < 2 {
+		graphHeight = 2
+	}
+
+	var b strings.Builder
+	for r := pressureResource(0); r < pressureResourceCount; r++ {
+		p := pressureOf(m.metrics.Pressure, r)
+		b.WriteString(MemoryLabelStyle.Render(fmt.Sprintf("%-8s", pressureNames[r])))
+		b.WriteString(formatPressureStats("some", p.Some))
+		b.WriteString("    ")
+		b.WriteString(formatPressureStats("full", p.Full))
+		b.WriteString("\n")
+
+		title := fmt.Sprintf(" %s pressure · some avg10, · = full avg10 ", pressureNames[r])
+		opts := GraphOptions{Reference: m.pressureHistories[r].full.GetLast(120)}
+		b.WriteString(m.renderGraphBox(title, m.pressureHistories[r].some.GetLast(120), graphHeight, opts))
+		b.WriteString("\n")
+	}
+	b.WriteString(HelpStyle.Render("averages over 10s, 60s and 300s  some: at least one task stalled  full: all non-idle tasks stalled at once"))
+
+	return b.String()
+}
+
+func formatPressureStats(kind string, s metrics.PressureStats) string {
+	avg := func(v float64) string {
+		return GetColorStyle(v).Render(fmt.Sprintf("%6.2f%%", v))
+	}
+	total := time.Duration(s.Total) * time.Microsecond
+	return fmt.Sprintf("%s %s %s %s  %s %s",
+		KeyStyle.Render(kind),
+		avg(s.Avg10), avg(s.Avg60), avg(s.Avg300),
+		HelpStyle.Render("stalled"), formatDuration(total),
+	)
+}
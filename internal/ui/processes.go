@@ -0,0 +1,297 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/user/cpu-monitor/internal/config"
+	"github.com/user/cpu-monitor/internal/metrics"
+	"github.com/user/cpu-monitor/internal/procctl"
+)
+
+// processSortColumn is the column the process table is ordered by.
+type processSortColumn int
+
+const (
+	sortByCPU processSortColumn = iota
+	sortByMemory
+	sortByPID
+	sortByUser
+	sortByThreads
+	sortByCommand
+	sortColumnCount
+)
+
+var sortColumnNames = [sortColumnCount]string{
+	sortByCPU:     "CPU%",
+	sortByMemory:  "MEM%",
+	sortByPID:     "PID",
+	sortByUser:    "USER",
+	sortByThreads: "THR",
+	sortByCommand: "COMMAND",
+}
+
+// processLess orders two processes by column in its natural direction:
+// busiest first for numeric load columns, ascending for identifiers.
+func processLess(a, b metrics.ProcessInfo, col processSortColumn) bool {
+	switch col {
+	case sortByMemory:
+		if a.RSS != b.RSS {
+			return a.RSS > b.RSS
+		}
+	case sortByPID:
+		return a.PID < b.PID
+	case sortByUser:
+		if a.User != b.User {
+			return a.User < b.User
+		}
+	case sortByThreads:
+		if a.Threads != b.Threads {
+			return a.Threads > b.Threads
+		}
+	case sortByCommand:
+		if a.Cmdline != b.Cmdline {
+			return a.Cmdline < b.Cmdline
+		}
+	default:
+		if a.CPUPercent != b.CPUPercent {
+			return a.CPUPercent > b.CPUPercent
+		}
+	}
+	return a.PID < b.PID
+}
+
+// sortedProcesses returns a sorted copy of the current process list; the
+// slice in CPUMetrics is shared with the collector and must not be reordered.
+func (m Model) sortedProcesses() []metrics.ProcessInfo {
+	if m.metrics == nil {
+		return nil
+	}
+	procs := make([]metrics.ProcessInfo, len(m.metrics.Processes))
+	copy(procs, m.metrics.Processes)
+	sort.SliceStable(procs, func(i, j int) bool {
+		if m.processSortReverse {
+			return processLess(procs[j], procs[i], m.processSort)
+		}
+		return processLess(procs[i], procs[j], m.processSort)
+	})
+	return procs
+}
+
+// selectedIndex returns the position of the selected PID, or 0 if it is gone.
+func selectedIndex(procs []metrics.ProcessInfo, pid int32) int {
+	for i, p := range procs {
+		if p.PID == pid {
+			return i
+		}
+	}
+	return 0
+}
+
+// processTableRows is how many process rows fit below the header, system
+// info and table header, leaving one line for the footer.
+func (m Model) processTableRows() int {
+	rows := m.height - 7 - m.actionDialogHeight()
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// visiblePIDs returns the PIDs in the order shown on the current screen.
+func (m Model) visiblePIDs() []int32 {
+	var pids []int32
+	if m.screen == screenTree {
+		for _, r := range m.treeRows() {
+			pids = append(pids, r.node.Process.PID)
+		}
+		return pids
+	}
+	for _, p := range m.sortedProcesses() {
+		pids = append(pids, p.PID)
+	}
+	return pids
+}
+
+// moveProcessSelection moves the highlighted row by delta, clamped to the list.
+func (m *Model) moveProcessSelection(delta int) {
+	pids := m.visiblePIDs()
+	if len(pids) == 0 {
+		return
+	}
+	idx := 0
+	for i, pid := range pids {
+		if pid == m.selectedPID {
+			idx = i
+			break
+		}
+	}
+	idx += delta
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(pids) {
+		idx = len(pids) - 1
+	}
+	m.selectedPID = pids[idx]
+}
+
+// syncSelection points selectedPID at the row drawn as selected when the
+// selected process is not listed, e.g. at startup or after it exited, so
+// actions, detail and collapse never target a PID that isn't shown.
+func (m *Model) syncSelection() {
+	if m.metrics == nil {
+		return
+	}
+	pids := m.visiblePIDs()
+	for _, pid := range pids {
+		if pid == m.selectedPID {
+			return
+		}
+	}
+	if len(pids) > 0 {
+		m.selectedPID = pids[0]
+	}
+}
+
+// scrollWindow returns the [start, end) range of rows to show so the
+// selected row stays roughly centered once the list scrolls.
+func scrollWindow(selected, visible, total int) (int, int) {
+	start := selected - visible/2
+	if start > total-visible {
+		start = total - visible
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + visible
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// handleProcessKey handles navigation and sorting keys on the process and
+// tree screens. It reports whether the key was consumed.
+func (m *Model) handleProcessKey(key string) bool {
+	switch key {
+	case "up":
+		m.moveProcessSelection(-1)
+	case "down":
+		m.moveProcessSelection(1)
+	case "pgup":
+		m.moveProcessSelection(-m.processTableRows())
+	case "pgdown":
+		m.moveProcessSelection(m.processTableRows())
+	case "home":
+		m.moveProcessSelection(-len(m.metrics.Processes))
+	case "end":
+		m.moveProcessSelection(len(m.metrics.Processes))
+	case "o":
+		m.processSort = (m.processSort + 1) % sortColumnCount
+	case "O":
+		m.processSortReverse = !m.processSortReverse
+	case "k", "n":
+		// Unsupported platforms leave k and n unbound
+		if !procctl.Supported {
+			return false
+		}
+		if key == "k" {
+			m.openAction(actionSignalMenu)
+		} else {
+			m.openAction(actionReniceMenu)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func (m Model) renderProcessesScreen() string {
+	procs := m.sortedProcesses()
+	if len(procs) == 0 {
+		return DimGrayStyle.Render("Collecting process list...")
+	}
+
+	selected := selectedIndex(procs, m.selectedPID)
+	start, end := scrollWindow(selected, m.processTableRows(), len(procs))
+
+	var b strings.Builder
+	b.WriteString(m.renderProcessHeader())
+	b.WriteString("\n")
+
+	for i := start; i < end; i++ {
+		b.WriteString(m.renderProcessRow(procs[i], i == selected))
+		b.WriteString("\n")
+	}
+
+	if m.action.mode != actionNone {
+		b.WriteString(m.renderActionDialog())
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.renderStatusLine())
+	b.WriteString(HelpStyle.Render(fmt.Sprintf(
+		"%d processes  ↑/↓ select  o: sort by %s  O: reverse%s",
+		len(procs), sortColumnNames[(m.processSort+1)%sortColumnCount], actionKeysHint())))
+
+	return b.String()
+}
+
+// Column layout shared by the header and rows
+const processRowFormat = "%7s %-9s %3s %6s %5s %9s %1s %4s %s"
+
+func (m Model) renderProcessHeader() string {
+	headers := sortColumnNames
+	arrow := "▼"
+	if m.processSortReverse {
+		arrow = "▲"
+	}
+	headers[m.processSort] += arrow
+
+	line := fmt.Sprintf(processRowFormat,
+		headers[sortByPID], headers[sortByUser], "NI", headers[sortByCPU], headers[sortByMemory],
+		"RSS", "S", headers[sortByThreads], headers[sortByCommand])
+	return ProcessHeaderStyle.Render(padRight(line, m.width))
+}
+
+func (m Model) renderProcessRow(p metrics.ProcessInfo, selected bool) string {
+	line := fmt.Sprintf(processRowFormat,
+		fmt.Sprintf("%d", p.PID),
+		truncateString(p.User, 9),
+		fmt.Sprintf("%d", p.Nice),
+		fmt.Sprintf("%.1f", p.CPUPercent),
+		fmt.Sprintf("%.1f", p.MemPercent),
+		formatBytes(p.RSS),
+		p.State,
+		fmt.Sprintf("%d", p.Threads),
+		p.Cmdline,
+	)
+	line = truncateString(line, m.width)
+
+	if selected {
+		return SelectedRowStyle.Render(padRight(line, m.width))
+	}
+
+	// Color the CPU column by load, matching the core bars
+	cpuStyle := lipgloss.NewStyle().Foreground(config.GetCPUColor(p.CPUPercent))
+	if p.CPUPercent < 0.1 {
+		cpuStyle = DimGrayStyle
+	}
+	prefix := fmt.Sprintf("%7d %-9s %3d ", p.PID, truncateString(p.User, 9), p.Nice)
+	cpu := fmt.Sprintf("%6.1f", p.CPUPercent)
+	if len(line) <= len(prefix)+len(cpu) {
+		return line
+	}
+	return prefix + cpuStyle.Render(cpu) + line[len(prefix)+len(cpu):]
+}
+
+// padRight pads s with spaces to width columns.
+func padRight(s string, width int) string {
+	if w := lipgloss.Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
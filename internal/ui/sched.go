@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/cpu-monitor/internal/config"
+	"github.com/user/cpu-monitor/internal/metrics"
+)
+
+// schedSparkWidth is the width of each sparkline in the scheduler line.
+const schedSparkWidth = 12
+
+// schedHistory keeps the /proc/stat scheduler figures for sparklines.
+type schedHistory struct {
+	ctxt    *metrics.History
+	intr    *metrics.History
+	forks   *metrics.History
+	running *metrics.History
+	blocked *metrics.History
+}
+
+func newSchedHistory(cfg config.Config) schedHistory {
+	newHist := func() *metrics.History {
+		return metrics.NewHistory(cfg.HistorySize, cfg.MovingAvgSize)
+	}
+	return schedHistory{ctxt: newHist(), intr: newHist(), forks: newHist(), running: newHist(), blocked: newHist()}
+}
+
+func (h schedHistory) add(cm *metrics.CPUMetrics) {
+	h.ctxt.Add(cm.ContextSwitchRate)
+	h.intr.Add(cm.InterruptRate)
+	h.forks.Add(cm.ForkRate)
+	h.running.Add(float64(cm.ProcsRunning))
+	h.blocked.Add(float64(cm.ProcsBlocked))
+}
+
+func (h schedHistory) reset() {
+	for _, hist := range []*metrics.History{h.ctxt, h.intr, h.forks, h.running, h.blocked} {
+		hist.Reset()
+	}
+}
+
+// renderSchedInfo shows scheduler rates and runnable/blocked tasks, each
+// with a sparkline of its recent history. Runnable tasks are scaled to the
+// CPU count so a full bar means every CPU has a task queued.
+func (m Model) renderSchedInfo() string {
+	spark := func(h *metrics.History, floor float64) string {
+		values := h.GetLast(schedSparkWidth)
+		return CreateSparkline(values, schedSparkWidth, historyPeak(values, floor))
+	}
+	cpus := float64(len(m.metrics.PerCoreUsage))
+	if cpus == 0 {
+		cpus = 1
+	}
+
+	blockedStyle := DimGrayStyle
+	if m.metrics.ProcsBlocked > 0 {
+		blockedStyle = OrangeStyle
+	}
+
+	parts := []string{
+		HelpStyle.Render("Ctx/s: ") + formatCount(m.metrics.ContextSwitchRate) + " " + spark(m.schedHistory.ctxt, 1),
+		HelpStyle.Render("Intr/s: ") + formatCount(m.metrics.InterruptRate) + " " + spark(m.schedHistory.intr, 1),
+		HelpStyle.Render("Forks/s: ") + formatCount(m.metrics.ForkRate) + " " + spark(m.schedHistory.forks, 1),
+		HelpStyle.Render("Running: ") + GetColorStyle(float64(m.metrics.ProcsRunning)/cpus*100).Render(fmt.Sprintf("%d", m.metrics.ProcsRunning)) +
+			" " + spark(m.schedHistory.running, cpus),
+		HelpStyle.Render("Blocked: ") + blockedStyle.Render(fmt.Sprintf("%d", m.metrics.ProcsBlocked)) +
+			" " + spark(m.schedHistory.blocked, cpus),
+	}
+	return strings.Join(parts, "  ")
+}
+
+// formatCount renders a rate compactly, e.g. 950, 12.3k, 4.5M.
+func formatCount(v float64) string {
+	switch {
+	case v >= 1e6:
+		return fmt.Sprintf("%.1fM", v/1e6)
+	case v >= 1e4:
+		return fmt.Sprintf("%.0fk", v/1e3)
+	case v >= 1e3:
+		return fmt.Sprintf("%.1fk", v/1e3)
+	}
+	return fmt.Sprintf("%.0f", v)
+}
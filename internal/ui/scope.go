@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/user/cpu-monitor/internal/metrics"
+)
+
+// maxScopeBars caps the per-process bars shown in scoped mode.
+const maxScopeBars = 12
+
+// scopeDescription summarizes what -pid/-name selected.
+func (m Model) scopeDescription() string {
+	if m.config.ScopeLabel != "" {
+		return m.config.ScopeLabel
+	}
+
+	var parts []string
+	if len(m.config.ScopePIDs) > 0 {
+		pids := make([]string, len(m.config.ScopePIDs))
+		for i, pid := range m.config.ScopePIDs {
+			pids[i] = fmt.Sprintf("%d", pid)
+		}
+		parts = append(parts, "pid "+strings.Join(pids, ","))
+	}
+	if m.config.ScopeName != nil {
+		parts = append(parts, fmt.Sprintf("name /%s/", m.config.ScopeName))
+	}
+	return strings.Join(parts, " ")
+}
+
+// renderScopeBars replaces the per-core bars when watching specific
+// processes: the scoped usage, the whole system as a reference, and one
+// bar per process (100% = one full CPU).
+func (m Model) renderScopeBars() string {
+	scope := m.metrics.Scope
+	var bars []string
+
+	bars = append(bars, CreateCPUBar("Scope CPU", scope.Usage, m.width))
+	bars = append(bars, CreateCPUBar("Moving Avg", m.history.GetMovingAverage(), m.width))
+	bars = append(bars, CreateStackedCPUBar("System", m.metrics.TotalBreakdown, m.width))
+
+	summary := fmt.Sprintf("Watching %s: %d matched, %d with descendants, %.1f%% of one CPU",
+		m.scopeDescription(), len(scope.Roots), len(scope.Processes), scope.CPUPercent)
+	bars = append(bars, ReferenceStyle.Render(summary))
+
+	if len(scope.Processes) == 0 {
+		bars = append(bars, DimGrayStyle.Render("No matching processes"))
+		return strings.Join(bars, "\n")
+	}
+
+	procs := make([]metrics.ProcessInfo, len(scope.Processes))
+	copy(procs, scope.Processes)
+	sort.SliceStable(procs, func(i, j int) bool {
+		return processLess(procs[i], procs[j], sortByCPU)
+	})
+	if len(procs) > maxScopeBars {
+		procs = procs[:maxScopeBars]
+	}
+
+	columns := 1
+	if m.width >= 100 {
+		columns = 2
+	}
+	columnWidth := m.width / columns
+	rowsNeeded := (len(procs) + columns - 1) / columns
+
+	for row := 0; row < rowsNeeded; row++ {
+		var rowBars []string
+		for col := 0; col < columns; col++ {
+			idx := row + col*rowsNeeded
+			if idx >= len(procs) {
+				rowBars = append(rowBars, strings.Repeat(" ", columnWidth-1))
+				continue
+			}
+			p := procs[idx]
+			label := truncateString(fmt.Sprintf("%d %s", p.PID, p.Name), 12)
+			rowBars = append(rowBars, CreateCPUBar(label, p.CPUPercent, columnWidth-1))
+		}
+		bars = append(bars, strings.Join(rowBars, " "))
+	}
+
+	return strings.Join(bars, "\n")
+}
+
+// renderScopeMemory shows the scoped RSS against system memory, with the
+// system-wide figure kept as a reference line.
+func (m Model) renderScopeMemory() string {
+	scope := m.metrics.Scope
+	memBar := CreateUsageBar("Memory:", scope.RSS, m.metrics.MemoryTotal, scope.MemoryUsage, m.width)
+	reference := fmt.Sprintf("System memory: %s/%s (%.1f%%)",
+		formatBytes(m.metrics.MemoryUsed), formatBytes(m.metrics.MemoryTotal), m.metrics.MemoryUsage)
+	return memBar + "\n" + ReferenceStyle.Render(reference)
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/user/cpu-monitor/internal/config"
+	"github.com/user/cpu-monitor/internal/metrics"
+)
+
+// renderSensorsScreen lists every temperature sensor with its thresholds,
+// then the kernel's thermal zones and CPU throttling. The sensor used as
+// CPU temperature is marked with an asterisk.
+func (m Model) renderSensorsScreen() string {
+	sensors := make([]metrics.SensorReading, len(m.metrics.Sensors))
+	copy(sensors, m.metrics.Sensors)
+	sort.SliceStable(sensors, func(i, j int) bool {
+		return sensors[i].Key < sensors[j].Key
+	})
+
+	if len(sensors) == 0 && len(m.metrics.ThermalZones) == 0 {
+		return DimGrayStyle.Render("No temperature sensors found") + "\n\n" + m.renderThrottleInfo()
+	}
+
+	keyWidth := 32
+	headerStyle := lipgloss.NewStyle().Foreground(config.Colors.NeonPurple).Bold(true)
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %-*s %8s %8s %8s  %s", keyWidth, "Sensor", "Temp", "High", "Crit", "Level")))
+	b.WriteString("\n")
+
+	// Bar width: marker(2) + key + 3 readings(27) + spacing(2)
+	barWidth := m.width - keyWidth - 31
+	if barWidth < 12 {
+		barWidth = 12
+	}
+
+	for _, s := range sensors {
+		marker := "  "
+		if s.Key == m.metrics.PackageSensor {
+			marker = KeyStyle.Render("* ")
+		}
+
+		// Scale the bar to the critical threshold when the sensor has one
+		limit := s.Critical
+		if limit <= 0 {
+			limit = 100
+		}
+		level := s.Temperature / limit * 100
+		style := GetColorStyle(level)
+
+		b.WriteString(marker)
+		b.WriteString(KeyStyle.Render(fmt.Sprintf("%-*s", keyWidth, truncateString(s.Key, keyWidth))))
+		b.WriteString(" ")
+		b.WriteString(style.Render(fmt.Sprintf("%7.1f°", s.Temperature)))
+		b.WriteString(" ")
+		b.WriteString(HelpStyle.Render(formatThreshold(s.High)))
+		b.WriteString(" ")
+		b.WriteString(HelpStyle.Render(formatThreshold(s.Critical)))
+		b.WriteString("  ")
+		b.WriteString(CreateProgressBar(level, barWidth, config.GetCPUColor(level)))
+		b.WriteString("\n")
+	}
+
+	if len(m.metrics.ThermalZones) > 0 {
+		b.WriteString("\n")
+		b.WriteString(m.renderThermalZones(keyWidth, barWidth))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.renderThrottleInfo())
+	b.WriteString("\n\n")
+	b.WriteString(HelpStyle.Render("* sensor used as CPU temperature (choose another with -sensor <key>)"))
+	if m.metrics.MissingSensor != "" {
+		b.WriteString("\n")
+		b.WriteString(RedStyle.Render(fmt.Sprintf("-sensor %q matches no sensor above; picked one automatically", m.metrics.MissingSensor)))
+	}
+
+	return b.String()
+}
+
+// renderThermalZones lists the kernel's thermal zones with the next trip
+// point above the current temperature. Bars are scaled to the critical
+// trip, or 100° without one.
+func (m Model) renderThermalZones(keyWidth, barWidth int) string {
+	headerStyle := lipgloss.NewStyle().Foreground(config.Colors.NeonPurple).Bold(true)
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %-*s %8s %17s  %s", keyWidth, "Thermal zone", "Temp", "Next trip", "Level")))
+	b.WriteString("\n")
+
+	for _, z := range m.metrics.ThermalZones {
+		limit := 100.0
+		for _, t := range z.Trips {
+			if t.Type == "critical" && t.Temperature > 0 {
+				limit = t.Temperature
+			}
+		}
+		level := z.Temperature / limit * 100
+
+		next := HelpStyle.Render(fmt.Sprintf("%17s", "-"))
+		if trip, ok := z.NextTrip(); ok {
+			next = HelpStyle.Render(fmt.Sprintf("%8s %7.1f°", truncateString(trip.Type, 8), trip.Temperature))
+		} else if len(z.Trips) > 0 {
+			// Every trip point is reached: the kernel is acting on it
+			next = RedStyle.Render(fmt.Sprintf("%17s", "all trips passed"))
+		}
+
+		name := z.Name
+		if z.Type != "" {
+			name = z.Type + " (" + strings.TrimPrefix(z.Name, "thermal_") + ")"
+		}
+		b.WriteString("  ")
+		b.WriteString(KeyStyle.Render(fmt.Sprintf("%-*s", keyWidth, truncateString(name, keyWidth))))
+		b.WriteString(" ")
+		b.WriteString(GetColorStyle(level).Render(fmt.Sprintf("%7.1f°", z.Temperature)))
+		b.WriteString(" ")
+		b.WriteString(next)
+		b.WriteString("  ")
+		b.WriteString(CreateProgressBar(level, barWidth, config.GetCPUColor(level)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderThrottleInfo summarizes the thermal_throttle counters: events in
+// the last interval and since boot, and which CPUs throttled.
+func (m Model) renderThrottleInfo() string {
+	t := m.metrics.Throttle
+	if !t.Available {
+		return DimGrayStyle.Render("Throttling: no thermal_throttle counters (non-Intel CPU or VM)")
+	}
+	line := fmt.Sprintf("Throttling: %d core / %d package events since boot", t.CoreTotal, t.PackageTotal)
+	if t.Events() == 0 {
+		return HelpStyle.Render(line + ", none since the last refresh")
+	}
+	now := fmt.Sprintf("%d core / %d package events since the last refresh", t.CoreEvents, t.PackageEvents)
+	if len(t.ThrottledCPUs) > 0 {
+		now += " on CPU " + formatCPUList(t.ThrottledCPUs)
+	}
+	return HelpStyle.Render(line+", ") + RedStyle.Render(now)
+}
+
+func formatThreshold(celsius float64) string {
+	if celsius <= 0 {
+		return fmt.Sprintf("%8s", "-")
+	}
+	return fmt.Sprintf("%7.1f°", celsius)
+}
@@ -49,6 +49,25 @@ var (
 	BracketStyle = lipgloss.NewStyle().
 		Foreground(config.Colors.DimGray)
 
+	FreqStyle = lipgloss.NewStyle().
+		Foreground(config.Colors.NeonBlue)
+
+	ProcessHeaderStyle = lipgloss.NewStyle().
+		Foreground(config.Colors.Background).
+		Background(config.Colors.NeonGreen).
+		Bold(true)
+
+	SelectedRowStyle = lipgloss.NewStyle().
+		Foreground(config.Colors.Background).
+		Background(config.Colors.NeonBlue)
+
+	ReferenceStyle = lipgloss.NewStyle().
+		Foreground(config.Colors.BrightWhite)
+
+	MarkerStyle = lipgloss.NewStyle().
+		Foreground(config.Colors.Red).
+		Bold(true)
+
 	GraphBorderStyle = lipgloss.NewStyle().
 		Foreground(config.Colors.NeonBlue)
 
@@ -84,6 +103,40 @@ var (
 	RedStyle    = lipgloss.NewStyle().Foreground(config.Colors.Red)
 )
 
+// Styles for each CPU state segment in stacked bars (htop-like palette)
+var (
+	UserStateStyle    = GreenStyle
+	NiceStateStyle    = BlueStyle
+	SystemStateStyle  = RedStyle
+	IrqStateStyle     = YellowStyle
+	SoftirqStateStyle = lipgloss.NewStyle().Foreground(config.Colors.NeonPurple)
+	StealStateStyle   = lipgloss.NewStyle().Foreground(config.Colors.NeonPink)
+	GuestStateStyle   = OrangeStyle
+	IowaitStateStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#808080"))
+)
+
+// Styles for C-state residency: active time, then idle states from
+// shallow to deep, cycling when a driver has more states
+var (
+	IdleActiveStyle = RedStyle
+	IdleStateStyles = []lipgloss.Style{
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#808080")),
+		YellowStyle,
+		BlueStyle,
+		lipgloss.NewStyle().Foreground(config.Colors.NeonPurple),
+		GreenStyle,
+		lipgloss.NewStyle().Foreground(config.Colors.NeonPink),
+	}
+)
+
+// Styles for the memory breakdown segments (htop-like palette)
+var (
+	MemUsedStyle    = GreenStyle
+	MemBuffersStyle = BlueStyle
+	MemSharedStyle  = lipgloss.NewStyle().Foreground(config.Colors.NeonPurple)
+	MemCacheStyle   = YellowStyle
+)
+
 func GetColorStyle(percentage float64) lipgloss.Style {
 	switch {
 	case percentage < 30:
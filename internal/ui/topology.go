@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/user/cpu-monitor/internal/metrics"
+)
+
+// topologyCore is one physical core and the indexes of its logical CPUs in
+// PerCoreUsage.
+type topologyCore struct {
+	id   int
+	cpus []int
+}
+
+// topologyNode is a NUMA node within a socket.
+type topologyNode struct {
+	socket int
+	node   int
+	cpus   []int
+	cores  []topologyCore
+}
+
+// groupTopology arranges CPU indexes by socket, then NUMA node, then
+// physical core, with SMT siblings together in CPU order.
+func groupTopology(topo []metrics.CPUTopology) []topologyNode {
+	type nodeKey struct{ socket, node int }
+	type coreKey struct{ socket, core int }
+
+	nodes := make(map[nodeKey]*topologyNode)
+	cores := make(map[coreKey]*topologyCore)
+	coreNode := make(map[coreKey]nodeKey)
+	for i, t := range topo {
+		nk := nodeKey{t.Package, t.Node}
+		n, ok := nodes[nk]
+		if !ok {
+			n = &topologyNode{socket: t.Package, node: t.Node}
+			nodes[nk] = n
+		}
+		n.cpus = append(n.cpus, i)
+
+		ck := coreKey{t.Package, t.Core}
+		c, ok := cores[ck]
+		if !ok {
+			c = &topologyCore{id: t.Core}
+			cores[ck] = c
+			coreNode[ck] = nk
+		}
+		c.cpus = append(c.cpus, i)
+	}
+
+	for ck, c := range cores {
+		sort.Slice(c.cpus, func(i, j int) bool { return topo[c.cpus[i]].CPU < topo[c.cpus[j]].CPU })
+		n := nodes[coreNode[ck]]
+		n.cores = append(n.cores, *c)
+	}
+
+	groups := make([]topologyNode, 0, len(nodes))
+	for _, n := range nodes {
+		sort.Slice(n.cores, func(i, j int) bool { return n.cores[i].id < n.cores[j].id })
+		groups = append(groups, *n)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].socket != groups[j].socket {
+			return groups[i].socket < groups[j].socket
+		}
+		return groups[i].node < groups[j].node
+	})
+	return groups
+}
+
+// averageUsage is the mean usage of the CPUs at the given indexes.
+func (m Model) averageUsage(cpus []int) float64 {
+	if len(cpus) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, i := range cpus {
+		if i < len(m.metrics.PerCoreUsage) {
+			sum += m.metrics.PerCoreUsage[i]
+		}
+	}
+	return sum / float64(len(cpus))
+}
+
+// renderTopologyBars replaces the flat per-CPU bars with bars grouped by
+// socket and NUMA node, each with an aggregate bar, and one cell per
+// physical core holding its SMT siblings side by side.
+func (m Model) renderTopologyBars() []string {
+	groups := groupTopology(m.metrics.Topology)
+
+	var bars []string
+	lastSocket := -1
+	for _, g := range groups {
+		if g.socket != lastSocket {
+			lastSocket = g.socket
+			var socketCPUs []int
+			for _, other := range groups {
+				if other.socket == g.socket {
+					socketCPUs = append(socketCPUs, other.cpus...)
+				}
+			}
+			bars = append(bars, CreateCPUBar(fmt.Sprintf("Socket %d", g.socket), m.averageUsage(socketCPUs), m.width))
+		}
+		if m.metrics.NodeCount > 1 {
+			bars = append(bars, CreateCPUBar(fmt.Sprintf("  Node %d", g.node), m.averageUsage(g.cpus), m.width))
+		}
+		bars = append(bars, m.renderCoreCells(g.cores)...)
+	}
+	return bars
+}
+
+// renderCoreCells lays out physical cores in columns like the flat view.
+func (m Model) renderCoreCells(cores []topologyCore) []string {
+	threads := 1
+	for _, c := range cores {
+		threads = max(threads, len(c.cpus))
+	}
+
+	columns := m.width / (36 * threads)
+	columns = max(1, min(columns, 4))
+	columnWidth := m.width / columns
+	rowsNeeded := (len(cores) + columns - 1) / columns
+
+	// Core label, then the sibling bars sharing the rest of the cell
+	const coreLabelWidth = 5
+	siblingWidth := (columnWidth - 1 - coreLabelWidth - (threads - 1)) / threads
+
+	var rows []string
+	for row := 0; row < rowsNeeded; row++ {
+		var cells []string
+		for col := 0; col < columns; col++ {
+			idx := row + col*rowsNeeded
+			if idx >= len(cores) {
+				cells = append(cells, strings.Repeat(" ", columnWidth-1))
+				continue
+			}
+			core := cores[idx]
+			cell := HelpStyle.Render(fmt.Sprintf("C%-*d", coreLabelWidth-1, core.id))
+			for n, i := range core.cpus {
+				if n > 0 {
+					cell += " "
+				}
+				label := fmt.Sprintf("CPU %d", m.metrics.Topology[i].CPU)
+				if i < len(m.metrics.PerCoreBreakdown) {
+					cell += CreateStackedCPUBar(label, m.metrics.PerCoreBreakdown[i], siblingWidth)
+				} else {
+					cell += CreateCPUBar(label, m.metrics.PerCoreUsage[i], siblingWidth)
+				}
+			}
+			cells = append(cells, padRight(cell, columnWidth-1))
+		}
+		rows = append(rows, strings.Join(cells, " "))
+	}
+	return rows
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/user/cpu-monitor/internal/config"
+	"github.com/user/cpu-monitor/internal/metrics"
+)
+
+// treeRow is one visible line of the process tree.
+type treeRow struct {
+	node   *metrics.ProcessNode
+	prefix string
+}
+
+// treeRows flattens the process tree into visible rows, skipping the
+// descendants of collapsed nodes.
+func (m Model) treeRows() []treeRow {
+	if m.metrics == nil {
+		return nil
+	}
+
+	var rows []treeRow
+	var walk func(node *metrics.ProcessNode, indent string, last bool, root bool)
+	walk = func(node *metrics.ProcessNode, indent string, last bool, root bool) {
+		prefix := indent
+		childIndent := indent
+		if !root {
+			if last {
+				prefix += "└─"
+				childIndent += "  "
+			} else {
+				prefix += "├─"
+				childIndent += "│ "
+			}
+		}
+		rows = append(rows, treeRow{node: node, prefix: prefix})
+
+		if m.collapsed[node.Process.PID] {
+			return
+		}
+		for i, child := range node.Children {
+			walk(child, childIndent, i == len(node.Children)-1, false)
+		}
+	}
+
+	for _, root := range metrics.BuildProcessTree(m.metrics.Processes) {
+		walk(root, "", true, true)
+	}
+	return rows
+}
+
+// handleTreeKey handles collapse and expand keys on the tree screen. It
+// reports whether the key was consumed.
+func (m *Model) handleTreeKey(key string) bool {
+	switch key {
+	case "left", "-":
+		m.collapsed[m.selectedPID] = true
+	case "right", "+":
+		delete(m.collapsed, m.selectedPID)
+	case " ":
+		if m.collapsed[m.selectedPID] {
+			delete(m.collapsed, m.selectedPID)
+		} else {
+			m.collapsed[m.selectedPID] = true
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func (m Model) renderTreeScreen() string {
+	rows := m.treeRows()
+	if len(rows) == 0 {
+		return DimGrayStyle.Render("Collecting process list...")
+	}
+
+	selected := 0
+	for i, r := range rows {
+		if r.node.Process.PID == m.selectedPID {
+			selected = i
+			break
+		}
+	}
+
+	visible := m.processTableRows()
+	start, end := scrollWindow(selected, visible, len(rows))
+
+	var b strings.Builder
+	header := fmt.Sprintf(treeRowFormat, "PID", "CPU%", "RSS", "ΣCPU%", "ΣRSS", "  COMMAND")
+	b.WriteString(ProcessHeaderStyle.Render(padRight(header, m.width)))
+	b.WriteString("\n")
+
+	for i := start; i < end; i++ {
+		b.WriteString(m.renderTreeRow(rows[i], i == selected))
+		b.WriteString("\n")
+	}
+
+	if m.action.mode != actionNone {
+		b.WriteString(m.renderActionDialog())
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.renderStatusLine())
+	b.WriteString(HelpStyle.Render(fmt.Sprintf(
+		"%d processes  ↑/↓ select  ←/→ collapse/expand  space: toggle%s  Σ = process and descendants",
+		len(m.metrics.Processes), actionKeysHint())))
+
+	return b.String()
+}
+
+const treeRowFormat = "%7s %6s %9s %6s %9s %s"
+
+func (m Model) renderTreeRow(r treeRow, selected bool) string {
+	p := r.node.Process
+
+	marker := "  "
+	if len(r.node.Children) > 0 {
+		if m.collapsed[p.PID] {
+			marker = "+ "
+		} else {
+			marker = "- "
+		}
+	}
+
+	name := p.Cmdline
+	if m.collapsed[p.PID] && len(r.node.Children) > 0 {
+		name = fmt.Sprintf("%s (%d hidden)", name, r.node.TreeCount-1)
+	}
+
+	line := fmt.Sprintf(treeRowFormat,
+		fmt.Sprintf("%d", p.PID),
+		fmt.Sprintf("%.1f", p.CPUPercent),
+		formatBytes(p.RSS),
+		fmt.Sprintf("%.1f", r.node.TreeCPU),
+		formatBytes(r.node.TreeRSS),
+		marker+r.prefix+name,
+	)
+	line = truncateString(line, m.width)
+
+	if selected {
+		return SelectedRowStyle.Render(padRight(line, m.width))
+	}
+
+	// Color the subtree CPU column so expensive branches stand out
+	cols := fmt.Sprintf("%7d %6.1f %9s ", p.PID, p.CPUPercent, formatBytes(p.RSS))
+	treeCPU := fmt.Sprintf("%6.1f", r.node.TreeCPU)
+	if len(line) <= len(cols)+len(treeCPU) {
+		return line
+	}
+	style := lipgloss.NewStyle().Foreground(config.GetCPUColor(r.node.TreeCPU))
+	if r.node.TreeCPU < 0.1 {
+		style = DimGrayStyle
+	}
+	return cols + style.Render(treeCPU) + line[len(cols)+len(treeCPU):]
+}
@@ -14,16 +14,116 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tickMsg:
+		cmds := []tea.Cmd{tickCmd(m.config.RefreshRate)}
 		if !m.paused {
-			m.collectMetrics()
 			m.spinnerFrame++
 			m.lastUpdate = time.Time(msg)
+			// Skip this tick if the previous collection is still running
+			if !m.collecting {
+				m.collecting = true
+				cmds = append(cmds, collectCmd(m.collector))
+			}
+			if m.detailPID != 0 && !m.collectingDetail {
+				m.collectingDetail = true
+				cmds = append(cmds, detailCmd(m.collector, m.detailPID))
+			}
+			if m.screen == screenCgroups && !m.collectingCgroups {
+				m.collectingCgroups = true
+				cmds = append(cmds, cgroupsCmd(m.cgroupCollector))
+			}
+			if m.screen == screenFrequency && !m.collectingFrequency {
+				m.collectingFrequency = true
+				cmds = append(cmds, frequencyCmd(m.collector))
+			}
+			if m.screen == screenCStates && !m.collectingIdle {
+				m.collectingIdle = true
+				cmds = append(cmds, idleCmd(m.collector))
+			}
+		}
+		return m, tea.Batch(cmds...)
+
+	case metricsMsg:
+		m.collecting = false
+		// Drop results that arrive after the user paused
+		if !m.paused || m.metrics == nil {
+			m.applyMetrics(msg)
+		}
+		return m, nil
+
+	case detailMsg:
+		m.applyDetail(msg)
+		return m, nil
+
+	case cgroupsMsg:
+		m.applyCgroups(msg)
+		return m, nil
+
+	case frequencyMsg:
+		m.applyFrequency(msg)
+		return m, nil
+
+	case idleMsg:
+		m.applyIdle(msg)
+		return m, nil
+
+	case actionResultMsg:
+		m.statusErr = msg.err != nil
+		m.statusLine = msg.status
+		if msg.err != nil {
+			m.statusLine = msg.err.Error()
 		}
-		return m, tickCmd(m.config.RefreshRate)
+		return m, nil
 
 	case tea.KeyMsg:
+		if m.action.mode != actionNone {
+			if msg.String() == "ctrl+c" {
+				m.interrupted = true
+				return m, tea.Quit
+			}
+			return m, m.handleActionKey(msg.String())
+		}
+
+		if m.detailPID != 0 && !m.showHelp {
+			switch msg.String() {
+			case "esc", "backspace", "left":
+				m.detailPID = 0
+				m.detail = nil
+				return m, nil
+			}
+		}
+
+		if !m.showHelp && m.metrics != nil && m.detailPID == 0 {
+			if msg.String() == "enter" && (m.screen == screenProcesses || m.screen == screenTree) {
+				return m, m.openDetail()
+			}
+
+			switch m.screen {
+			case screenDashboard:
+				if msg.String() == "g" {
+					m.groupByTopology = !m.groupByTopology
+					return m, nil
+				}
+			case screenProcesses:
+				if m.handleProcessKey(msg.String()) {
+					return m, nil
+				}
+			case screenTree:
+				if m.handleProcessKey(msg.String()) || m.handleTreeKey(msg.String()) {
+					return m, nil
+				}
+			case screenNetwork:
+				if m.handleNetworkKey(msg.String()) {
+					return m, nil
+				}
+			}
+		}
+
 		switch msg.String() {
-		case "q", "ctrl+c":
+		case "q":
+			return m, tea.Quit
+
+		case "ctrl+c":
+			m.interrupted = true
 			return m, tea.Quit
 		
 		case "r":
@@ -41,6 +141,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "h":
 			m.showHelp = !m.showHelp
 			return m, nil
+
+		case "tab":
+			if !m.showHelp {
+				// The detail view belongs to the screen it was opened from
+				m.detailPID = 0
+				m.detail = nil
+				m.screen = (m.screen + 1) % screenCount
+				m.syncSelection()
+			}
+			return m, nil
+
+		case "shift+tab":
+			if !m.showHelp {
+				m.detailPID = 0
+				m.detail = nil
+				m.screen = (m.screen + screenCount - 1) % screenCount
+				m.syncSelection()
+			}
+			return m, nil
 		}
 	}
 
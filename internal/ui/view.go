@@ -2,11 +2,13 @@ package ui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/user/cpu-monitor/internal/config"
+	"github.com/user/cpu-monitor/internal/procctl"
 )
 
 func (m Model) View() string {
@@ -26,11 +28,52 @@ func (m Model) View() string {
 	b.WriteString("\n")
 	b.WriteString(m.renderSystemInfo())
 	b.WriteString("\n\n")
+
+	if m.detailPID != 0 {
+		b.WriteString(m.renderDetailScreen())
+		return b.String()
+	}
+
+	switch m.screen {
+	case screenSensors:
+		b.WriteString(m.renderSensorsScreen())
+		return b.String()
+	case screenProcesses:
+		b.WriteString(m.renderProcessesScreen())
+		return b.String()
+	case screenTree:
+		b.WriteString(m.renderTreeScreen())
+		return b.String()
+	case screenPressure:
+		b.WriteString(m.renderPressureScreen())
+		return b.String()
+	case screenCgroups:
+		b.WriteString(m.renderCgroupsScreen())
+		return b.String()
+	case screenDisks:
+		b.WriteString(m.renderDisksScreen())
+		return b.String()
+	case screenNetwork:
+		b.WriteString(m.renderNetworkScreen())
+		return b.String()
+	case screenIRQs:
+		b.WriteString(m.renderIRQScreen())
+		return b.String()
+	case screenFrequency:
+		b.WriteString(m.renderFrequencyScreen())
+		return b.String()
+	case screenCStates:
+		b.WriteString(m.renderCStatesScreen())
+		return b.String()
+	}
+
 	b.WriteString(m.renderCPUBars())
 	b.WriteString("\n")
 	b.WriteString(m.renderGraph())
 	b.WriteString("\n")
 	b.WriteString(m.renderMemoryInfo())
+	b.WriteString("\n")
+	b.WriteString(m.renderSwapInfo())
 	b.WriteString("\n\n")
 	b.WriteString(m.renderBottomInfo())
 	
@@ -38,9 +81,14 @@ func (m Model) View() string {
 }
 
 func (m Model) renderHeader() string {
-	title := TitleStyle.Render(config.AppTitle)
+	screenName := screenNames[m.screen]
+	if m.detailPID != 0 {
+		screenName = fmt.Sprintf("Process %d", m.detailPID)
+	}
+	title := TitleStyle.Render(config.AppTitle) + HelpStyle.Render(" › ") + KeyStyle.Render(screenName)
 	
 	controls := []string{
+		KeyStyle.Render("tab") + HelpStyle.Render(":screens"),
 		KeyStyle.Render("h") + HelpStyle.Render(":help"),
 		KeyStyle.Render("q") + HelpStyle.Render(":quit"),
 		KeyStyle.Render("r") + HelpStyle.Render(":reset"),
@@ -77,14 +125,29 @@ func (m Model) renderSystemInfo() string {
 		tempStyle := GetColorStyle(m.metrics.Temperature)
 		tempStr = tempStyle.Render(fmt.Sprintf("%.1f°C", m.metrics.Temperature))
 	}
+	if m.metrics.MissingSensor != "" {
+		tempStr += " " + RedStyle.Render(fmt.Sprintf("(no sensor %q)", truncateString(m.metrics.MissingSensor, 24)))
+	}
+	if m.metrics.Throttle.Events() > 0 {
+		tempStr += " " + RedStyle.Bold(true).Render("THROTTLED")
+	}
+
+	freqStr := fmt.Sprintf("%.0f MHz", m.metrics.Frequency)
+
+	cores := fmt.Sprintf("Cores: %d  Threads: %d", m.metrics.CoreCount, m.metrics.ThreadCount)
+	if len(m.metrics.OfflineCPUs) > 0 {
+		cores += "  " + DimGrayStyle.Render(fmt.Sprintf("Offline: %d", len(m.metrics.OfflineCPUs)))
+	}
+	if m.metrics.SocketCount > 1 || m.metrics.NodeCount > 1 {
+		cores = fmt.Sprintf("Sockets: %d  Nodes: %d  ", m.metrics.SocketCount, m.metrics.NodeCount) + cores
+	}
 
 	info := fmt.Sprintf(
-		"%s  CPU: %s  Cores: %d  Threads: %d  Freq: %.0f MHz  Temp: %s  %s",
+		"%s  CPU: %s  %s  Freq: %s  Temp: %s  %s",
 		statusIndicator,
 		truncateString(m.metrics.ModelName, 20),
-		m.metrics.CoreCount,
-		m.metrics.ThreadCount,
-		m.metrics.Frequency,
+		cores,
+		FreqStyle.Render(freqStr),
 		tempStr,
 		TimeStyle.Render(currentTime),
 	)
@@ -93,26 +156,45 @@ func (m Model) renderSystemInfo() string {
 }
 
 func (m Model) renderCPUBars() string {
+	if m.metrics.Scope != nil {
+		return m.renderScopeBars()
+	}
+
 	barWidth := m.width
 
 	var bars []string
 
 	// Show total and moving average at the top
-	totalBar := CreateCPUBar("Total CPU", m.metrics.TotalUsage, barWidth)
+	totalBar := CreateStackedCPUBar("Total CPU", m.metrics.TotalBreakdown, barWidth)
 	bars = append(bars, totalBar)
 
 	movingAvg := m.history.GetMovingAverage()
 	avgBar := CreateCPUBar("Moving Avg", movingAvg, barWidth)
 	bars = append(bars, avgBar)
 
-	bars = append(bars, "")
+	bars = append(bars, CreateCPUStateLegend(m.metrics.TotalBreakdown, m.width))
+
+	if m.metrics.Cgroup != nil {
+		bars = append(bars, m.renderCgroupBar())
+	}
 
 	// Calculate layout for per-core display
-	numCores := len(m.metrics.PerCoreUsage)
-	if numCores == 0 {
+	if len(m.metrics.PerCoreUsage) == 0 {
 		return strings.Join(bars, "\n")
 	}
 
+	if line := m.renderCoreFrequencyStats(); line != "" {
+		bars = append(bars, line)
+	}
+
+	if m.groupByTopology && len(m.metrics.Topology) == len(m.metrics.PerCoreUsage) {
+		bars = append(bars, m.renderTopologyBars()...)
+		return strings.Join(bars, "\n")
+	}
+
+	cells := m.cpuCells()
+	numCores := len(cells)
+
 	// Determine number of columns based on terminal width
 	// Each column needs roughly 40 characters minimum
 	minColumnWidth := 40
@@ -135,13 +217,35 @@ func (m Model) renderCPUBars() string {
 		var rowBars []string
 		
 		for col := 0; col < maxColumns; col++ {
-			coreIndex := row + col*rowsNeeded
-			if coreIndex >= numCores {
+			cellIndex := row + col*rowsNeeded
+			if cellIndex >= numCores {
 				// Add empty space for alignment
 				rowBars = append(rowBars, strings.Repeat(" ", columnWidth-1))
+			} else if cells[cellIndex].index < 0 {
+				rowBars = append(rowBars, m.renderOfflineCPU(cells[cellIndex].id, columnWidth-1))
 			} else {
-				label := fmt.Sprintf("Core %d", coreIndex)
-				bar := CreateCPUBar(label, m.metrics.PerCoreUsage[coreIndex], columnWidth-1)
+				// These are logical CPUs; label them by kernel CPU number
+				coreIndex := cells[cellIndex].index
+				label := fmt.Sprintf("CPU %d", cells[cellIndex].id)
+				cellWidth := columnWidth - 1
+				freqStr := ""
+				if coreIndex < len(m.metrics.PerCoreFrequency) && m.metrics.PerCoreFrequency[coreIndex] > 0 {
+					freqStr = " " + FreqStyle.Render(formatFrequency(m.metrics.PerCoreFrequency[coreIndex]))
+				}
+				if coreIndex < len(m.metrics.PerCoreTemperature) && m.metrics.PerCoreTemperature[coreIndex] > 0 {
+					temp := m.metrics.PerCoreTemperature[coreIndex]
+					freqStr += " " + GetColorStyle(temp).Render(fmt.Sprintf("%3.0f°", temp))
+				}
+				cellWidth -= lipgloss.Width(freqStr)
+				spark := m.coreSparkline(cells[cellIndex].id, cellWidth)
+				cellWidth -= lipgloss.Width(spark)
+				var bar string
+				if coreIndex < len(m.metrics.PerCoreBreakdown) {
+					bar = CreateStackedCPUBar(label, m.metrics.PerCoreBreakdown[coreIndex], cellWidth)
+				} else {
+					bar = CreateCPUBar(label, m.metrics.PerCoreUsage[coreIndex], cellWidth)
+				}
+				bar += spark + freqStr
 				rowBars = append(rowBars, bar)
 			}
 		}
@@ -152,20 +256,102 @@ func (m Model) renderCPUBars() string {
 	return strings.Join(bars, "\n")
 }
 
+// renderCoreFrequencyStats summarizes the per-core clocks shown beside
+// the bars; empty without cpufreq.
+func (m Model) renderCoreFrequencyStats() string {
+	minFreq, maxFreq, avgFreq := m.metrics.FrequencyStats()
+	if avgFreq <= 0 {
+		return ""
+	}
+	return HelpStyle.Render("Core freq  avg ") + FreqStyle.Render(formatFrequency(avgFreq)) +
+		HelpStyle.Render("  min ") + FreqStyle.Render(formatFrequency(minFreq)) +
+		HelpStyle.Render("  max ") + FreqStyle.Render(formatFrequency(maxFreq))
+}
+
+// cpuCell is one entry of the flat CPU bar grid: an index into the
+// per-core slices, or -1 for an offline CPU.
+type cpuCell struct {
+	id    int
+	index int
+}
+
+// cpuCells lists online and offline CPUs in kernel CPU order.
+func (m Model) cpuCells() []cpuCell {
+	cells := make([]cpuCell, 0, len(m.metrics.PerCoreUsage)+len(m.metrics.OfflineCPUs))
+	for i := range m.metrics.PerCoreUsage {
+		id := i
+		if i < len(m.metrics.CPUIDs) {
+			id = m.metrics.CPUIDs[i]
+		}
+		cells = append(cells, cpuCell{id: id, index: i})
+	}
+	for _, id := range m.metrics.OfflineCPUs {
+		cells = append(cells, cpuCell{id: id, index: -1})
+	}
+	sort.SliceStable(cells, func(i, j int) bool { return cells[i].id < cells[j].id })
+	return cells
+}
+
+// coreSparkline draws the recent usage of CPU id in a quarter of a cell
+// of the given width, with a leading space; empty when it wouldn't fit.
+func (m Model) coreSparkline(id, width int) string {
+	sparkWidth := min(width/4, 16)
+	if sparkWidth < 4 {
+		return ""
+	}
+	var values []float64
+	if hist, ok := m.coreHistories[id]; ok {
+		values = hist.GetLast(sparkWidth)
+	}
+	return " " + CreateSparkline(values, sparkWidth, 100)
+}
+
+// renderOfflineCPU fills an offline CPU's cell in place of its bar. The
+// samples from before it went offline stay on the right.
+func (m Model) renderOfflineCPU(id, width int) string {
+	label := fmt.Sprintf("CPU %d", id)
+	labelWidth := 12
+	if width < 50 {
+		label = fmt.Sprintf("%2d", id)
+		labelWidth = 5
+	}
+	spark := m.coreSparkline(id, width)
+	cell := lipgloss.NewStyle().Foreground(config.Colors.NeonBlue).Width(labelWidth).Render(label) +
+		DimGrayStyle.Render("offline")
+	return padRight(cell, width-lipgloss.Width(spark)) + spark
+}
+
 func (m Model) renderGraph() string {
+	titleText := " CPU History (60s) "
+	var opts GraphOptions
+	if m.metrics.Scope != nil {
+		titleText = " Scope CPU History (60s) · = system "
+		opts.Reference = m.systemHistory.GetLast(120)
+	}
+	// Mark samples taken right after the CPUs throttled
+	throttled := false
+	for _, events := range m.throttleHistory.GetLast(120) {
+		opts.Markers = append(opts.Markers, events > 0)
+		throttled = throttled || events > 0
+	}
+	if throttled {
+		titleText += "▼ = throttled "
+	}
+	return m.renderGraphBox(titleText, m.history.GetLast(120), 8, opts)
+}
+
+// renderGraphBox draws a full-width history graph in a titled border.
+func (m Model) renderGraphBox(titleText string, values []float64, graphHeight int, opts GraphOptions) string {
 	graphWidth := m.width
-	graphHeight := 8
 
 	titleStyle := lipgloss.NewStyle().
 		Foreground(config.Colors.NeonPurple).
 		Bold(true)
 
-	titleText := " CPU History (60s) "
 	title := titleStyle.Render(titleText)
 	titleWidth := lipgloss.Width(titleText)
 
-	historyValues := m.history.GetLast(120)
-	graph := CreateASCIIGraph(historyValues, graphWidth-2, graphHeight)
+	graph := CreateASCIIGraphWithOptions(values, graphWidth-2, graphHeight, opts)
 
 	borderStyle := lipgloss.NewStyle().
 		Foreground(config.Colors.NeonBlue)
@@ -207,9 +393,46 @@ func (m Model) renderGraph() string {
 }
 
 func (m Model) renderMemoryInfo() string {
+	if m.metrics.Scope != nil {
+		return m.renderScopeMemory()
+	}
+
 	barWidth := m.width
-	memBar := CreateMemoryBar(m.metrics.MemoryUsed, m.metrics.MemoryTotal, m.metrics.MemoryUsage, barWidth)
-	return memBar
+	memBar := CreateMemoryBar(m.metrics.Memory, m.metrics.MemoryUsage, barWidth)
+	legend := strings.Repeat(" ", 13) + CreateMemoryLegend(m.metrics.Memory)
+	if cg := m.metrics.Cgroup; cg != nil && cg.MemoryMax > 0 {
+		legend += "\n" + CreateUsageBar("Cgroup mem:", cg.MemoryCurrent, cg.MemoryMax, cg.MemoryUsage, barWidth)
+	}
+	return memBar + "\n" + legend
+}
+
+// renderSwapInfo draws the swap bar and a line with paging rates. Swap-in
+// or swap-out activity is highlighted since it usually explains slowness.
+func (m Model) renderSwapInfo() string {
+	var swapLine string
+	if m.metrics.SwapTotal > 0 {
+		swapLine = CreateSwapBar(m.metrics.SwapUsed, m.metrics.SwapTotal, m.metrics.SwapUsage, m.width)
+	} else {
+		swapLine = MemoryLabelStyle.Render("Swap:") + " " + DimGrayStyle.Render("no swap configured")
+	}
+
+	rateStyle := func(rate float64) lipgloss.Style {
+		if rate > 0 {
+			return OrangeStyle
+		}
+		return DimGrayStyle
+	}
+
+	rates := fmt.Sprintf("%s %s  %s %s  %s %s",
+		HelpStyle.Render("swap in:"),
+		rateStyle(m.metrics.SwapInRate).Render(formatBytes(uint64(m.metrics.SwapInRate))+"/s"),
+		HelpStyle.Render("swap out:"),
+		rateStyle(m.metrics.SwapOutRate).Render(formatBytes(uint64(m.metrics.SwapOutRate))+"/s"),
+		HelpStyle.Render("major faults:"),
+		rateStyle(m.metrics.MajorFaultRate).Render(fmt.Sprintf("%.0f/s", m.metrics.MajorFaultRate)),
+	)
+
+	return swapLine + "\n" + strings.Repeat(" ", 13) + rates
 }
 
 func (m Model) renderBottomInfo() string {
@@ -240,14 +463,26 @@ func (m Model) renderBottomInfo() string {
 		Foreground(config.Colors.DimGray).
 		Render(strings.Repeat("─", m.width))
 
-	return separator + "\n" + info
+	return separator + "\n" + info + "\n" + m.renderSchedInfo()
 }
 
 func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
 		return s
 	}
-	return s[:maxLen-3] + "..."
+	if maxLen <= 3 {
+		return string(runes[:maxLen])
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
+
+// formatFrequency renders MHz compactly for display beside core bars.
+func formatFrequency(mhz float64) string {
+	if mhz >= 1000 {
+		return fmt.Sprintf("%.2fG", mhz/1000)
+	}
+	return fmt.Sprintf("%.0fM", mhz)
 }
 
 func formatDuration(d time.Duration) string {
@@ -299,14 +534,28 @@ func (m Model) renderHelpScreen() string {
 	b.WriteString(sectionStyle.Render("Keyboard Shortcuts"))
 	b.WriteString("\n\n")
 	
-	shortcuts := []struct {
+	type shortcut struct {
 		key  string
 		desc string
-	}{
+	}
+	shortcuts := []shortcut{
 		{"h", "Toggle this help screen"},
 		{"q, Ctrl+C", "Quit the application"},
 		{"r", "Reset CPU history"},
 		{"p", "Pause/unpause monitoring"},
+		{"Tab, Shift+Tab", "Switch between screens"},
+		{"↑/↓, PgUp/PgDn", "Select a process (Processes screen)"},
+		{"o / O", "Change sort column / reverse order (Processes screen)"},
+		{"←/→, space", "Collapse/expand the selected branch (Tree screen)"},
+		{"g", "Group CPU bars by socket, NUMA node and physical core (Dashboard)"},
+		{"l / v / b", "Show/hide loopback, veth and bridge interfaces (Network screen)"},
+		{"Enter / Esc", "Open / close the detail screen of the selected process"},
+	}
+	if procctl.Supported {
+		shortcuts = append(shortcuts,
+			shortcut{"k", "Send SIGTERM/SIGKILL/SIGSTOP/SIGCONT to the selected process"},
+			shortcut{"n", "Change the nice value of the selected process"},
+		)
 	}
 	
 	for _, s := range shortcuts {
@@ -327,10 +576,27 @@ func (m Model) renderHelpScreen() string {
 	}{
 		{"Total CPU", "Overall system CPU usage percentage"},
 		{"Moving Avg", "10-sample moving average of CPU usage"},
-		{"Core Bars", "Individual CPU core usage (multi-column layout for many cores)"},
+		{"Cgroup CPU", "Usage of our cgroup v2 against its CPU quota, with throttling"},
+		{"CPU Bars", "Usage of each logical CPU; g groups them by socket, NUMA node and core"},
+		{"Bar Colors", "usr, nice, sys, irq, softirq, steal and guest time; iowait is idle and only in the legend"},
+		{"Core Freq", "Current frequency beside each core, with avg, min and max above the bars"},
+		{"Core Temp", "Per-core temperature beside each core (coretemp sensors)"},
+		{"Throttling", "THROTTLED beside Temp and ▼ on the CPU history when the CPUs thermally throttled"},
+		{"Sensors", "Every temperature sensor and thermal zone with its thresholds and trip points"},
+		{"Processes", "PID, user, CPU%, memory, state, threads and command of every process"},
+		{"Tree", "Parent/child process hierarchy with per-subtree CPU and memory"},
+		{"Pressure", "CPU, memory and IO stall information (PSI) with history graphs"},
+		{"Cgroups", "Cgroup v2 groups and systemd units ranked by CPU, with memory and throttling"},
+		{"Disks", "Per-disk throughput, IOPS, latency and utilization with sparklines"},
+		{"Network", "Per-interface rx/tx throughput, packets, errors and drops with sparklines"},
+		{"IRQs", "Heatmap of the busiest interrupts and softirqs per CPU"},
+		{"Frequency", "cpufreq driver, governor, EPP and limits per CPU, with time in each frequency"},
+		{"C-states", "Share of time each CPU spends active and in each idle state, with exit latencies"},
 		{"CPU History", "60-second graph of CPU usage over time"},
-		{"Memory", "System RAM usage and availability"},
+		{"Memory", "RAM split into used, buffers, shared and cache, with available, slab, dirty and hugepages"},
+		{"Swap", "Swap usage plus swap-in/out and major page fault rates"},
 		{"System Info", "Load average, process count, uptime"},
+		{"Scheduler", "Context switch, interrupt and fork rates; runnable and blocked tasks"},
 	}
 	
 	for _, s := range sections {
@@ -386,6 +652,11 @@ func (m Model) renderHelpScreen() string {
 		{"-refresh ms", "Set refresh rate in milliseconds (100-5000, default: 500)"},
 		{"-history n", "Number of history points to keep (default: 120)"},
 		{"-avg n", "Moving average window size (default: 10)"},
+		{"-sysfs path", "Path where sysfs is mounted (default: /sys)"},
+		{"-procfs path", "Path where procfs is mounted (default: /proc)"},
+		{"-sensor key", "Temperature sensor used as CPU temperature (default: auto)"},
+		{"-pid pid", "Monitor only this process and its descendants (repeatable)"},
+		{"-name regexp", "Monitor only processes whose name or command matches"},
 		{"-help", "Show command line help"},
 	}
 	